@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/rules"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/scenario"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+func largeContext() types.DiagnosticContext {
+	return scenario.New("mydata").
+		Nodes(500).
+		Workers(5000).
+		TaintedNodes(50).
+		OOMKilledWorkers(200).
+		MemoryPendingWorkers(300).
+		Build()
+}
+
+// BenchmarkIndexBuild measures the one-off cost of indexing a context, which
+// every Analyze call pays before evaluating rules.
+func BenchmarkIndexBuild(b *testing.B) {
+	ctx := largeContext()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.Build(ctx)
+	}
+}
+
+func BenchmarkAnalyze(b *testing.B) {
+	ctx := largeContext()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Analyze(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// The rules that existed before the index, as linear scans over the raw
+// context and in their current indexed form, for a side-by-side comparison.
+func linearRules() []Evaluator {
+	return []Evaluator{
+		Adapt(&linearFuseUnschedulable{}),
+		Adapt(&linearWorkerPendingMemory{}),
+		Adapt(&linearRuntimePartiallyReady{}),
+		Adapt(&linearPVCUnbound{}),
+		Adapt(&linearDatasetNotBound{}),
+	}
+}
+
+func indexedRules() []Evaluator {
+	return []Evaluator{
+		&rules.FuseUnschedulableRule{},
+		&rules.WorkerPendingMemoryRule{},
+		&rules.RuntimePartiallyReadyRule{},
+		&rules.PVCUnboundRule{},
+		&rules.DatasetNotBoundRule{},
+	}
+}
+
+// benchmarkRules times rule evaluation alone on a prebuilt index; the linear
+// rules ignore it and scan the raw context. Add BenchmarkIndexBuild to the
+// indexed figure for the full cost of the indexed path.
+func benchmarkRules(b *testing.B, evaluators []Evaluator) {
+	idx := index.Build(largeContext())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := evaluateRules(context.Background(), evaluators, idx, 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRules_Linear(b *testing.B) {
+	benchmarkRules(b, linearRules())
+}
+
+func BenchmarkRules_Indexed(b *testing.B) {
+	benchmarkRules(b, indexedRules())
+}
@@ -1,10 +1,11 @@
 package engine
 
 import (
+	"context"
 	"sort"
 	"time"
 
-	"github.com/mrhapile/fluid-ai-diagnoser/pkg/rules"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
 	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
 )
 
@@ -13,37 +14,85 @@ import (
 //   - Never mutates the input
 //   - Never performs I/O
 //   - Produces deterministic, repeatable output
-func Analyze(ctx types.DiagnosticContext) (types.DiagnosisResult, error) {
-	// Initialize all rules
-	allRules := []Rule{
-		&rules.FuseUnschedulableRule{},
-		&rules.WorkerPendingMemoryRule{},
-		&rules.RuntimePartiallyReadyRule{},
-		&rules.PVCUnboundRule{},
-		&rules.DatasetNotBoundRule{},
+//
+// The rule pack is selected automatically from the context's cluster version,
+// collector version and runtime types; see WithRulePacks. A rule that panics
+// is reported in DiagnosisResult.RuleErrors; see WithErrorPolicy.
+func Analyze(ctx types.DiagnosticContext, opts ...Option) (types.DiagnosisResult, error) {
+	return AnalyzeContext(context.Background(), ctx, opts...)
+}
+
+// AnalyzeContext is like Analyze but stops evaluating rules once runCtx is
+// cancelled or its deadline passes, returning runCtx.Err(). A rule that is
+// already running is allowed to finish. With WithConcurrency, rules are
+// evaluated in parallel; the result is identical to the sequential path.
+func AnalyzeContext(runCtx context.Context, ctx types.DiagnosticContext, opts ...Option) (types.DiagnosisResult, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var scopeName string
+	if o.scope != nil {
+		scoped, err := applyScope(ctx, *o.scope)
+		if err != nil {
+			return types.DiagnosisResult{}, err
+		}
+		ctx, scopeName = scoped, o.scope.String()
+	}
+
+	pack, err := SelectRulePack(o.packs, ctx)
+	if err != nil {
+		return types.DiagnosisResult{}, err
+	}
+
+	rules, err := applicableRules(pack.Rules, ctx)
+	if err != nil {
+		return types.DiagnosisResult{}, err
+	}
+
+	idx := index.BuildWithClassifier(ctx, o.classifier)
+	outcomes, err := evaluateRules(runCtx, rules, idx, o.concurrency)
+	if err != nil {
+		return types.DiagnosisResult{}, err
 	}
 
 	var hypotheses []types.Hypothesis
+	var ruleErrors []types.RuleError
 
-	// Apply each rule
-	for _, rule := range allRules {
-		if rule.Match(ctx) {
-			h := rule.Hypothesis(ctx)
-			hypotheses = append(hypotheses, h)
+	// Merge outcomes in rule order so that concurrency never affects output
+	for _, out := range outcomes {
+		if out.err != nil {
+			if o.errorPolicy == FailOnRuleError {
+				return types.DiagnosisResult{}, *out.err
+			}
+			ruleErrors = append(ruleErrors, *out.err)
+			continue
 		}
+		hypotheses = append(hypotheses, out.hypotheses...)
 	}
 
+	annotateImpact(idx, hypotheses)
+
 	// Sort by confidence (descending) for deterministic ordering
 	sort.SliceStable(hypotheses, func(i, j int) bool {
+		// Optionally: most affected workloads first
+		if ni, nj := impactSize(hypotheses[i]), impactSize(hypotheses[j]); o.rankImpact && ni != nj {
+			return ni > nj
+		}
 		// Primary: higher confidence first
 		if hypotheses[i].Confidence != hypotheses[j].Confidence {
 			return hypotheses[i].Confidence > hypotheses[j].Confidence
 		}
-		// Secondary: alphabetical by component for stability
+		// Secondary: more severe first
+		if si, sj := severityOrder(hypotheses[i]), severityOrder(hypotheses[j]); si != sj {
+			return si < sj
+		}
+		// Tertiary: alphabetical by component for stability
 		if hypotheses[i].Component != hypotheses[j].Component {
 			return hypotheses[i].Component < hypotheses[j].Component
 		}
-		// Tertiary: alphabetical by issue
+		// Finally: alphabetical by issue
 		return hypotheses[i].Issue < hypotheses[j].Issue
 	})
 
@@ -52,9 +101,26 @@ func Analyze(ctx types.DiagnosticContext) (types.DiagnosisResult, error) {
 		hypotheses[i].Rank = i + 1
 	}
 
-	return types.DiagnosisResult{
-		Hypotheses:  hypotheses,
-		GeneratedAt: time.Now().UTC(),
-		Engine:      "rule-based",
-	}, nil
+	result := types.DiagnosisResult{
+		Hypotheses:      hypotheses,
+		GeneratedAt:     time.Now().UTC(),
+		Engine:          "rule-based",
+		RulePack:        pack.Name,
+		RulePackVersion: pack.Version,
+		RuleErrors:      ruleErrors,
+		Scope:           scopeName,
+		Health:          assessHealth(ctx, hypotheses, ruleErrors),
+	}
+	if o.groupByData {
+		result.Datasets, result.Cluster = groupByDataset(idx, hypotheses)
+	}
+	return result, nil
+}
+
+// severityOrder sorts hypotheses without a severity after SeverityLow.
+func severityOrder(h types.Hypothesis) int {
+	if h.Severity == 0 {
+		return types.SeverityLow + 1
+	}
+	return h.Severity
 }
@@ -1,8 +1,17 @@
 package engine
 
 import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/scenario"
 	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
 )
 
@@ -26,6 +35,9 @@ func TestAnalyze_EmptyContext(t *testing.T) {
 func TestAnalyze_FuseUnschedulable(t *testing.T) {
 	ctx := types.DiagnosticContext{
 		Graph: types.ResourceGraph{
+			Runtimes: map[string]types.RuntimeInfo{
+				"mydata": {Name: "mydata", Namespace: "default"},
+			},
 			Pods: map[string]types.PodInfo{
 				"mydata-fuse-abc123": {
 					Name:      "mydata-fuse-abc123",
@@ -76,6 +88,9 @@ func TestAnalyze_FuseUnschedulable(t *testing.T) {
 func TestAnalyze_WorkerPendingMemory(t *testing.T) {
 	ctx := types.DiagnosticContext{
 		Graph: types.ResourceGraph{
+			Runtimes: map[string]types.RuntimeInfo{
+				"mydata": {Name: "mydata", Namespace: "default"},
+			},
 			Pods: map[string]types.PodInfo{
 				"mydata-worker-0": {
 					Name:      "mydata-worker-0",
@@ -302,3 +317,1819 @@ func TestAnalyze_RankingByConfidence(t *testing.T) {
 		}
 	}
 }
+
+func TestAnalyze_RecordsRulePack(t *testing.T) {
+	result, err := Analyze(types.DiagnosticContext{})
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	if result.RulePack != "core" {
+		t.Errorf("Expected rule pack 'core', got '%s'", result.RulePack)
+	}
+	if result.RulePackVersion == "" {
+		t.Error("Expected rule pack version to be recorded")
+	}
+}
+
+func TestAnalyze_SelectsCompatibleRulePack(t *testing.T) {
+	legacy := RulePack{
+		Name:    "legacy",
+		Version: "v0.1.0",
+		Compatibility: Compatibility{
+			KubernetesVersions: "<1.20",
+		},
+	}
+	juicefs := RulePack{
+		Name:    "juicefs",
+		Version: "v0.2.0",
+		Compatibility: Compatibility{
+			CollectorVersions: ">=0.1.0",
+			RuntimeTypes:      []string{"JuiceFS"},
+		},
+	}
+	fallback := RulePack{Name: "fallback", Version: "v1.0.0"}
+
+	ctx := types.DiagnosticContext{
+		Summary:  types.Summary{ClusterVersion: "v1.28.3-eks-4f4795d"},
+		Metadata: types.Metadata{CollectorVersion: "v0.1.0"},
+		Graph: types.ResourceGraph{
+			Runtimes: map[string]types.RuntimeInfo{
+				"mydata": {Name: "mydata", Type: "juicefs"},
+			},
+		},
+	}
+
+	result, err := Analyze(ctx, WithRulePacks(legacy, juicefs, fallback))
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if result.RulePack != "juicefs" || result.RulePackVersion != "v0.2.0" {
+		t.Errorf("Expected juicefs@v0.2.0, got %s@%s", result.RulePack, result.RulePackVersion)
+	}
+
+	ctx.Graph.Runtimes["mydata"] = types.RuntimeInfo{Name: "mydata", Type: "Alluxio"}
+	result, err = Analyze(ctx, WithRulePacks(legacy, juicefs, fallback))
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if result.RulePack != "fallback" {
+		t.Errorf("Expected fallback pack for Alluxio runtime, got %s", result.RulePack)
+	}
+}
+
+func TestAnalyze_NoCompatibleRulePack(t *testing.T) {
+	pack := RulePack{
+		Name:          "modern",
+		Version:       "v1.0.0",
+		Compatibility: Compatibility{KubernetesVersions: ">=1.30"},
+	}
+	ctx := types.DiagnosticContext{
+		Summary: types.Summary{ClusterVersion: "v1.28.0"},
+	}
+
+	if _, err := Analyze(ctx, WithRulePacks(pack)); err == nil {
+		t.Error("Expected error when no rule pack supports the context")
+	}
+
+	// A missing cluster version must not exclude the pack.
+	if _, err := Analyze(types.DiagnosticContext{}, WithRulePacks(pack)); err != nil {
+		t.Errorf("Expected pack to be selected when cluster version is unknown, got %v", err)
+	}
+}
+
+func TestAnalyze_RulePackFluidVersions(t *testing.T) {
+	legacy := RulePack{Name: "legacy", Version: "v0.1.0", Compatibility: Compatibility{FluidVersions: "<1.0.0"}}
+	fallback := RulePack{Name: "fallback", Version: "v1.0.0"}
+
+	for fluidVersion, want := range map[string]string{
+		"v0.9.2": "legacy",
+		"v1.0.3": "fallback",
+		"":       "legacy",
+	} {
+		ctx := types.DiagnosticContext{
+			Summary:  types.Summary{FluidVersion: fluidVersion},
+			Metadata: types.Metadata{CollectorVersion: "v1.0.3"},
+		}
+		result, err := Analyze(ctx, WithRulePacks(legacy, fallback))
+		if err != nil {
+			t.Fatalf("Analyze returned error: %v", err)
+		}
+		if result.RulePack != want {
+			t.Errorf("Fluid %q: expected pack %s, got %s", fluidVersion, want, result.RulePack)
+		}
+	}
+}
+
+// sidecarRule only applies to clusters with native sidecar containers.
+type sidecarRule struct {
+	constraint string
+}
+
+func (r *sidecarRule) ID() string {
+	return "sidecar"
+}
+
+func (r *sidecarRule) KubernetesVersions() string {
+	return r.constraint
+}
+
+func (r *sidecarRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	return []types.Hypothesis{{Component: "Fuse", Issue: "sidecar", Evidence: []string{"always"}}}
+}
+
+func TestAnalyze_VersionedEvaluator(t *testing.T) {
+	pack := RulePack{Name: "test", Version: "v1.0.0", Rules: []Evaluator{&sidecarRule{constraint: ">=1.29"}}}
+
+	for clusterVersion, want := range map[string]int{"v1.28.5": 0, "v1.29.0-gke.1": 1, "": 1} {
+		ctx := types.DiagnosticContext{Summary: types.Summary{ClusterVersion: clusterVersion}}
+		result, err := Analyze(ctx, WithRulePacks(pack))
+		if err != nil {
+			t.Fatalf("Analyze returned error: %v", err)
+		}
+		if len(result.Hypotheses) != want {
+			t.Errorf("Cluster version %q: expected %d hypotheses, got %d", clusterVersion, want, len(result.Hypotheses))
+		}
+	}
+
+	pack.Rules = []Evaluator{&sidecarRule{constraint: ">=next"}}
+	if _, err := Analyze(types.DiagnosticContext{}, WithRulePacks(pack)); err == nil {
+		t.Error("Expected a malformed rule constraint to be reported")
+	}
+}
+
+func TestAnalyze_UnsupportedClusterVersion(t *testing.T) {
+	tests := []struct {
+		cluster, fluid string
+		want           string
+	}{
+		{"v1.23.4", "v0.7.0", "Cluster version v1.23.4, Fluid v0.7.0 supports Kubernetes >=1.14 <1.22"},
+		{"v1.16.8", "v0.9.3", "Cluster version v1.16.8, Fluid v0.9.3 supports Kubernetes >=1.18"},
+		{"v1.30.1", "v1.0.3", ""},
+		{"v1.30.1", "", ""},
+	}
+
+	for _, tt := range tests {
+		ctx := scenario.New("mydata").ClusterVersion(tt.cluster).FluidVersion(tt.fluid).Build()
+		result, err := Analyze(ctx)
+		if err != nil {
+			t.Fatalf("Analyze returned error: %v", err)
+		}
+		var got string
+		for _, h := range result.Hypotheses {
+			if h.Component == "Cluster" {
+				got = h.Evidence[0]
+				if len(h.Evidence) != 2 || !strings.HasPrefix(h.Evidence[1], "Support range: ") {
+					t.Errorf("Expected the basis of the support range as evidence, got %v", h.Evidence)
+				}
+			}
+		}
+		if got != tt.want {
+			t.Errorf("Kubernetes %s, Fluid %q: expected %q, got %q", tt.cluster, tt.fluid, tt.want, got)
+		}
+	}
+}
+
+func TestAnalyze_HealthyScenario(t *testing.T) {
+	ctx := scenario.New("mydata").Nodes(3).Workers(3).Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 0 {
+		t.Errorf("Expected no hypotheses for a healthy deployment, got %+v", result.Hypotheses)
+	}
+}
+
+func TestAnalyze_DegradedScenario(t *testing.T) {
+	ctx := scenario.New("mydata").
+		Nodes(3).
+		Workers(3).
+		TaintedNodes(1).
+		MemoryPendingWorkers(1).
+		Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	components := map[string]bool{}
+	for _, h := range result.Hypotheses {
+		components[h.Component] = true
+	}
+	for _, want := range []string{"Fuse", "Worker", "Runtime"} {
+		if !components[want] {
+			t.Errorf("Expected a %s hypothesis, got %+v", want, result.Hypotheses)
+		}
+	}
+}
+
+// panickingRule panics in the configured phase.
+type panickingRule struct {
+	phase string
+}
+
+func (r *panickingRule) ID() string {
+	return "panicking-" + r.phase
+}
+
+func (r *panickingRule) Match(ctx types.DiagnosticContext) bool {
+	if r.phase == PhaseMatch {
+		var pods []types.PodInfo
+		_ = pods[1]
+	}
+	return true
+}
+
+func (r *panickingRule) Hypothesis(ctx types.DiagnosticContext) types.Hypothesis {
+	var runtime *types.RuntimeInfo
+	return types.Hypothesis{Component: "Runtime", Issue: runtime.Name}
+}
+
+func TestAnalyze_RulePanicIsIsolated(t *testing.T) {
+	pack := DefaultRulePacks()[0]
+	pack.Rules = append([]Evaluator{
+		Adapt(&panickingRule{phase: PhaseMatch}),
+		Adapt(&panickingRule{phase: PhaseHypothesis}),
+	}, pack.Rules...)
+
+	ctx := types.DiagnosticContext{
+		Graph: types.ResourceGraph{
+			Datasets: map[string]types.DatasetInfo{
+				"mydata": {Name: "mydata", Namespace: "default", Status: "NotBound"},
+			},
+		},
+	}
+
+	result, err := Analyze(ctx, WithRulePacks(pack))
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	if len(result.Hypotheses) != 1 || result.Hypotheses[0].Component != "Dataset" {
+		t.Errorf("Expected the Dataset hypothesis to survive, got %+v", result.Hypotheses)
+	}
+
+	if len(result.RuleErrors) != 2 {
+		t.Fatalf("Expected 2 rule errors, got %+v", result.RuleErrors)
+	}
+	for i, phase := range []string{PhaseMatch, PhaseHypothesis} {
+		e := result.RuleErrors[i]
+		if e.RuleID != "panicking-"+phase || e.Phase != phase || e.Message == "" {
+			t.Errorf("Unexpected rule error %+v", e)
+		}
+	}
+}
+
+func TestAnalyze_FailOnRuleError(t *testing.T) {
+	pack := RulePack{
+		Name:    "faulty",
+		Version: "v0.0.1",
+		Rules:   []Evaluator{Adapt(&panickingRule{phase: PhaseHypothesis})},
+	}
+
+	_, err := Analyze(types.DiagnosticContext{}, WithRulePacks(pack), WithErrorPolicy(FailOnRuleError))
+	if err == nil {
+		t.Fatal("Expected Analyze to fail")
+	}
+
+	var ruleErr types.RuleError
+	if !errors.As(err, &ruleErr) {
+		t.Fatalf("Expected a types.RuleError, got %T", err)
+	}
+	if ruleErr.RuleID != "panicking-hypothesis" || ruleErr.Phase != PhaseHypothesis {
+		t.Errorf("Unexpected rule error %+v", ruleErr)
+	}
+}
+
+// slowRule blocks in Match until its delay has passed.
+type slowRule struct {
+	delay time.Duration
+}
+
+func (r *slowRule) ID() string {
+	return "slow"
+}
+
+func (r *slowRule) Match(ctx types.DiagnosticContext) bool {
+	time.Sleep(r.delay)
+	return false
+}
+
+func (r *slowRule) Hypothesis(ctx types.DiagnosticContext) types.Hypothesis {
+	return types.Hypothesis{}
+}
+
+func TestAnalyzeContext_Cancelled(t *testing.T) {
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, n := range []int{1, 4} {
+		_, err := AnalyzeContext(runCtx, types.DiagnosticContext{}, WithConcurrency(n))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Concurrency %d: expected context.Canceled, got %v", n, err)
+		}
+	}
+}
+
+func TestAnalyzeContext_Deadline(t *testing.T) {
+	pack := RulePack{Name: "slow", Version: "v0.0.1"}
+	for i := 0; i < 10; i++ {
+		pack.Rules = append(pack.Rules, Adapt(&slowRule{delay: 20 * time.Millisecond}))
+	}
+
+	runCtx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := AnalyzeContext(runCtx, types.DiagnosticContext{}, WithRulePacks(pack))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("Expected evaluation to stop at the deadline, took %v", elapsed)
+	}
+}
+
+func TestAnalyzeContext_ConcurrentMatchesSequential(t *testing.T) {
+	ctx := scenario.New("mydata").
+		Nodes(50).
+		Workers(500).
+		TaintedNodes(10).
+		OOMKilledWorkers(20).
+		MemoryPendingWorkers(30).
+		Build()
+
+	pack := DefaultRulePacks()[0]
+	pack.Rules = append(pack.Rules, Adapt(&panickingRule{phase: PhaseHypothesis}))
+
+	sequential, err := Analyze(ctx, WithRulePacks(pack))
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	for _, n := range []int{2, 4, 16} {
+		concurrent, err := AnalyzeContext(context.Background(), ctx, WithRulePacks(pack), WithConcurrency(n))
+		if err != nil {
+			t.Fatalf("AnalyzeContext returned error: %v", err)
+		}
+		concurrent.GeneratedAt = sequential.GeneratedAt
+		if !reflect.DeepEqual(sequential, concurrent) {
+			t.Errorf("Concurrency %d produced different output:\nsequential %+v\nconcurrent %+v",
+				n, sequential, concurrent)
+		}
+	}
+}
+
+// splitRule reports one hypothesis per not-ready runtime.
+type splitRule struct{}
+
+func (r *splitRule) ID() string {
+	return "split"
+}
+
+func (r *splitRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var out []types.Hypothesis
+	for _, name := range idx.RuntimeNames() {
+		runtime := idx.Context().Graph.Runtimes[name]
+		if runtime.Phase != "Ready" {
+			out = append(out, types.Hypothesis{
+				Confidence: types.ConfidenceConditionOnly,
+				Component:  "Runtime",
+				Issue:      "Runtime " + name + " is not ready",
+				Evidence:   []string{"Runtime " + name + ": Phase=" + runtime.Phase},
+			})
+		}
+	}
+	return out
+}
+
+// indexedOnlyRule implements IndexedRule and fails if the unindexed methods are used.
+type indexedOnlyRule struct{}
+
+func (r *indexedOnlyRule) ID() string {
+	return "indexed-only"
+}
+
+func (r *indexedOnlyRule) Match(ctx types.DiagnosticContext) bool {
+	panic("Match called")
+}
+
+func (r *indexedOnlyRule) Hypothesis(ctx types.DiagnosticContext) types.Hypothesis {
+	panic("Hypothesis called")
+}
+
+func (r *indexedOnlyRule) MatchIndexed(idx *index.Index) bool {
+	return len(idx.RuntimeNames()) > 0
+}
+
+func (r *indexedOnlyRule) HypothesisIndexed(idx *index.Index) types.Hypothesis {
+	return types.Hypothesis{Component: "Runtime", Issue: "indexed", Evidence: idx.RuntimeNames()}
+}
+
+func TestAnalyze_EvaluatorReturnsMultipleHypotheses(t *testing.T) {
+	ctx := types.DiagnosticContext{
+		Graph: types.ResourceGraph{
+			Runtimes: map[string]types.RuntimeInfo{
+				"a": {Name: "a", Phase: "NotReady"},
+				"b": {Name: "b", Phase: "Ready"},
+				"c": {Name: "c", Phase: "PartialReady"},
+			},
+		},
+	}
+	pack := RulePack{
+		Name:    "custom",
+		Version: "v0.0.1",
+		Rules:   []Evaluator{&splitRule{}, Adapt(&indexedOnlyRule{})},
+	}
+
+	result, err := Analyze(ctx, WithRulePacks(pack))
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.RuleErrors) != 0 {
+		t.Fatalf("Unexpected rule errors: %+v", result.RuleErrors)
+	}
+
+	var issues []string
+	for _, h := range result.Hypotheses {
+		issues = append(issues, h.Issue)
+	}
+	want := []string{"Runtime a is not ready", "Runtime c is not ready", "indexed"}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("Expected issues %v, got %v", want, issues)
+	}
+}
+
+func TestAnalyze_RuntimeCitesOnlyReadyCondition(t *testing.T) {
+	ctx := types.DiagnosticContext{
+		Graph: types.ResourceGraph{
+			Runtimes: map[string]types.RuntimeInfo{
+				"mydata": {
+					Name:      "mydata",
+					Namespace: "default",
+					Conditions: []types.Condition{
+						{Type: "Ready", Status: "False", Reason: "WorkerNotReady"},
+						{Type: "FusesReady", Status: "False", Reason: "FuseNotReady"},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 1 {
+		t.Fatalf("Expected 1 hypothesis, got %+v", result.Hypotheses)
+	}
+	evidence := result.Hypotheses[0].Evidence
+	if len(evidence) != 1 || evidence[0] != "Runtime default/mydata: Condition Ready=False, reason=WorkerNotReady" {
+		t.Errorf("Expected only the Ready condition to be cited, got %v", evidence)
+	}
+}
+
+func TestAnalyze_AppPodIsNotTreatedAsWorker(t *testing.T) {
+	ctx := types.DiagnosticContext{
+		Graph: types.ResourceGraph{
+			Pods: map[string]types.PodInfo{
+				"workerpool-api-6d5f-abcde": {
+					Name:            "workerpool-api-6d5f-abcde",
+					Namespace:       "default",
+					Status:          "Pending",
+					OwnerReferences: []types.OwnerReference{{Kind: "ReplicaSet", Name: "workerpool-api-6d5f"}},
+					Conditions: []types.Condition{
+						{Type: "PodScheduled", Status: "False", Reason: "Unschedulable", Message: "0/3 nodes are available: 3 Insufficient memory."},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 0 {
+		t.Errorf("Expected no hypotheses for an application pod, got %+v", result.Hypotheses)
+	}
+
+	classifier, err := roles.NewClassifier(append([]roles.MappingRule{
+		{Name: "workerpool", Role: roles.Worker, OwnerName: "workerpool-*"},
+	}, roles.DefaultRules()...)...)
+	if err != nil {
+		t.Fatalf("NewClassifier returned error: %v", err)
+	}
+
+	result, err = Analyze(ctx, WithRoleClassifier(classifier))
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 1 || result.Hypotheses[0].Component != "Worker" {
+		t.Errorf("Expected a Worker hypothesis with the custom classifier, got %+v", result.Hypotheses)
+	}
+}
+
+func TestAnalyze_MasterJournalFailure(t *testing.T) {
+	ctx := scenario.New("mydata").Workers(2).CrashLoopingMasters(1).Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) == 0 {
+		t.Fatal("Expected at least one hypothesis")
+	}
+
+	top := result.Hypotheses[0]
+	if top.Component != "Master" || top.Severity != types.SeverityCritical {
+		t.Fatalf("Expected a critical Master hypothesis first, got %+v", top)
+	}
+	if top.Confidence != types.ConfidenceEventAndStatus {
+		t.Errorf("Expected confidence %v with pod and log evidence, got %v", types.ConfidenceEventAndStatus, top.Confidence)
+	}
+	if top.Issue != "Runtime master is failing on journal or format errors, blocking the entire Dataset" {
+		t.Errorf("Expected the journal-specific issue, got %q", top.Issue)
+	}
+
+	for _, h := range result.Hypotheses {
+		if h.Component != "Runtime" {
+			continue
+		}
+		for _, e := range h.Evidence {
+			if strings.Contains(e, ": Master ") {
+				t.Errorf("Runtime hypothesis should leave master shortfalls to the Master rule, got %q", e)
+			}
+		}
+	}
+}
+
+func TestAnalyze_MasterLeaderElection(t *testing.T) {
+	ctx := types.DiagnosticContext{
+		Graph: types.ResourceGraph{
+			Runtimes: map[string]types.RuntimeInfo{
+				"mydata": {Name: "mydata", Namespace: "default", Type: "Alluxio", MasterReplicas: 3, MasterReady: 1},
+			},
+		},
+		Logs: map[string]string{
+			"alluxio-master": "INFO Starting master\nWARN Raft group has no leader after 30s\nINFO Retrying",
+		},
+	}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 1 {
+		t.Fatalf("Expected 1 hypothesis, got %+v", result.Hypotheses)
+	}
+	h := result.Hypotheses[0]
+	if h.Issue != "Runtime master cannot establish HA leadership, blocking the entire Dataset" {
+		t.Errorf("Expected the HA-specific issue, got %q", h.Issue)
+	}
+	if h.Confidence != types.ConfidenceEventAndStatus {
+		t.Errorf("Expected status and log confidence, got %v", h.Confidence)
+	}
+	if len(h.Evidence) != 2 {
+		t.Errorf("Expected replica and log evidence, got %v", h.Evidence)
+	}
+}
+
+func TestAnalyze_MasterOutranksWorkers(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  types.DiagnosticContext
+	}{
+		{"crash-looping master", scenario.New("mydata").Workers(2).CrashLoopingMasters(1).MemoryPendingWorkers(1).Build()},
+		{"master status only", types.DiagnosticContext{
+			Graph: types.ResourceGraph{
+				Runtimes: map[string]types.RuntimeInfo{
+					"mydata": {Name: "mydata", Namespace: "default", Type: "Alluxio",
+						MasterReplicas: 1, MasterReady: 0, WorkerReplicas: 2, WorkerReady: 1},
+				},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Analyze(tt.ctx)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+			if len(result.Hypotheses) < 2 {
+				t.Fatalf("Expected master and worker hypotheses, got %+v", result.Hypotheses)
+			}
+			if top := result.Hypotheses[0]; top.Component != "Master" || top.Rank != 1 {
+				t.Errorf("Expected the critical Master hypothesis to rank first, got %+v", result.Hypotheses)
+			}
+		})
+	}
+}
+
+func TestAnalyze_HealthyControlPlane(t *testing.T) {
+	ctx := scenario.New("mydata").Nodes(2).Workers(2).ControlPlane().Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 0 {
+		t.Errorf("Expected no hypotheses for a healthy control plane, got %+v", result.Hypotheses)
+	}
+}
+
+func TestAnalyze_ControllerCrashLooping(t *testing.T) {
+	ctx := scenario.New("mydata").Workers(2).CrashLoopingController().Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) == 0 {
+		t.Fatal("Expected at least one hypothesis")
+	}
+
+	top := result.Hypotheses[0]
+	if top.Component != "Controller" || top.Severity != types.SeverityCritical {
+		t.Fatalf("Expected a critical Controller hypothesis first, got %+v", top)
+	}
+	if top.Confidence != types.ConfidenceEventAndStatus {
+		t.Errorf("Expected confidence %v with pod and event evidence, got %v", types.ConfidenceEventAndStatus, top.Confidence)
+	}
+
+	var stale *types.Hypothesis
+	for i, h := range result.Hypotheses {
+		if strings.Contains(h.Issue, "stale") {
+			stale = &result.Hypotheses[i]
+		}
+	}
+	if stale == nil {
+		t.Fatalf("Expected a stale-status hypothesis, got %+v", result.Hypotheses)
+	}
+	if stale.Issue != "Runtime or Dataset status is stale because the Fluid controller is unhealthy" {
+		t.Errorf("Expected stale status to be attributed to the controller, got %q", stale.Issue)
+	}
+}
+
+func TestAnalyze_StaleStatusWithoutController(t *testing.T) {
+	ctx := scenario.New("mydata").Workers(2).Build()
+	runtime := ctx.Graph.Runtimes["mydata"]
+	runtime.WorkerReady = 0
+	ctx.Graph.Runtimes["mydata"] = runtime
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	for _, h := range result.Hypotheses {
+		if h.Component != "Controller" {
+			continue
+		}
+		if h.Confidence != types.ConfidenceLow {
+			t.Errorf("Expected low confidence without controller evidence, got %v", h.Confidence)
+		}
+		return
+	}
+	t.Errorf("Expected a Controller hypothesis for the status mismatch, got %+v", result.Hypotheses)
+}
+
+func TestAnalyze_WebhookUnavailable(t *testing.T) {
+	ctx := types.DiagnosticContext{
+		Graph: types.ResourceGraph{
+			ControlPlane: map[string]types.PodInfo{
+				"fluid-webhook-5d8c7b9f4-abcde": {
+					Name:      "fluid-webhook-5d8c7b9f4-abcde",
+					Namespace: "fluid-system",
+					Status:    "Pending",
+					Labels:    map[string]string{"control-plane": "fluid-webhook"},
+				},
+			},
+		},
+		Events: []types.Event{
+			{
+				Type:   "Warning",
+				Reason: "FailedCreate",
+				Message: `Error creating: Internal error occurred: failed calling webhook "fluid-pod-admission-webhook.fluid.io": ` +
+					`dial tcp 10.96.0.12:9443: connect: connection refused`,
+				InvolvedObject: types.ObjectReference{Kind: "ReplicaSet", Name: "app-7f9c", Namespace: "default"},
+			},
+		},
+	}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	for _, h := range result.Hypotheses {
+		if h.Component != "Webhook" {
+			continue
+		}
+		if h.Confidence != types.ConfidenceEventAndStatus {
+			t.Errorf("Expected confidence %v with webhook pod evidence, got %v", types.ConfidenceEventAndStatus, h.Confidence)
+		}
+		if len(h.Evidence) != 2 {
+			t.Errorf("Expected event and pod evidence, got %v", h.Evidence)
+		}
+		if !strings.Contains(h.Evidence[0], "webhook fluid-pod-admission-webhook.fluid.io:") {
+			t.Errorf("Expected the webhook name in the evidence, got %q", h.Evidence[0])
+		}
+		return
+	}
+	t.Errorf("Expected a Webhook hypothesis, got %+v", result.Hypotheses)
+}
+
+func TestAnalyze_OtherWebhookUnavailable(t *testing.T) {
+	ctx := scenario.New("mydata").Workers(2).ControlPlane().Build()
+	ctx.Events = append(ctx.Events, types.Event{
+		Type:   "Warning",
+		Reason: "FailedCreate",
+		Message: `Error creating: Internal error occurred: failed calling webhook "sidecar-injector.istio.io": ` +
+			`Post "https://istiod.istio-system.svc:443/inject": dial tcp 10.96.0.40:443: connect: connection refused`,
+		InvolvedObject: types.ObjectReference{Kind: "ReplicaSet", Name: "app-7f9c", Namespace: "default"},
+	})
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	for _, h := range result.Hypotheses {
+		if h.Component == "Webhook" {
+			t.Errorf("Expected no Webhook hypothesis for a non-Fluid webhook, got %+v", h)
+		}
+	}
+}
+
+func TestAnalyze_DataLoadOOMKilled(t *testing.T) {
+	ctx := scenario.New("mydata").Workers(2).OOMKilledDataLoad().Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 1 {
+		t.Fatalf("Expected 1 hypothesis, got %+v", result.Hypotheses)
+	}
+
+	h := result.Hypotheses[0]
+	if h.Component != "DataOperation" || h.Issue != "DataLoad job was OOMKilled" {
+		t.Errorf("Expected the DataLoad OOM hypothesis, got %+v", h)
+	}
+	if h.Confidence != types.ConfidenceEventAndStatus {
+		t.Errorf("Expected confidence %v with job pod evidence, got %v", types.ConfidenceEventAndStatus, h.Confidence)
+	}
+}
+
+func TestAnalyze_DataLoadRestartedAfterOOM(t *testing.T) {
+	ctx := scenario.New("mydata").Workers(2).RestartedDataLoad().Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 1 || result.Hypotheses[0].Issue != "DataLoad job was OOMKilled" {
+		t.Fatalf("Expected the DataLoad OOM hypothesis, got %+v", result.Hypotheses)
+	}
+	evidence := result.Hypotheses[0].Evidence
+	if len(evidence) != 1 || !strings.Contains(evidence[0], "container dataloader OOMKilled, restarts=1") {
+		t.Errorf("Expected the earlier OOMKill as evidence, got %v", evidence)
+	}
+}
+
+func TestAnalyze_CompletedDataLoadWithOOMHistory(t *testing.T) {
+	ctx := scenario.New("mydata").Workers(2).DataLoad().Build()
+	for name, pod := range ctx.Graph.Pods {
+		if len(pod.OwnerReferences) == 0 || pod.OwnerReferences[0].Kind != "Job" {
+			continue
+		}
+		pod.ContainerStatuses[0].RestartCount = 1
+		pod.ContainerStatuses[0].LastTerminationReason = "OOMKilled"
+		ctx.Graph.Pods[name] = pod
+	}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 0 {
+		t.Errorf("Expected no hypotheses for a completed DataLoad, got %+v", result.Hypotheses)
+	}
+}
+
+func TestAnalyze_DataLoadWaitingOnDataset(t *testing.T) {
+	ctx := scenario.New("mydata").WithoutRuntime().DataLoad().Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	for _, h := range result.Hypotheses {
+		if h.Component == "DataOperation" {
+			if h.Issue != "DataLoad is waiting on a Dataset that is not bound" {
+				t.Errorf("Expected the unbound-dataset issue, got %q", h.Issue)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected a DataOperation hypothesis, got %+v", result.Hypotheses)
+}
+
+func TestAnalyze_DataLoadJobUnschedulable(t *testing.T) {
+	ctx := scenario.New("mydata").DataLoad().Build()
+	op := ctx.Graph.DataOperations["mydata-warmup"]
+	op.Phase = "Executing"
+	ctx.Graph.DataOperations[op.Name] = op
+	for name, pod := range ctx.Graph.Pods {
+		if len(pod.OwnerReferences) > 0 && pod.OwnerReferences[0].Kind == "Job" {
+			pod.Status, pod.NodeName, pod.ContainerStatuses = "Pending", "", nil
+			ctx.Graph.Pods[name] = pod
+			ctx.Events = append(ctx.Events, types.Event{
+				Type:           "Warning",
+				Reason:         "FailedScheduling",
+				Message:        "0/1 nodes are available: 1 Insufficient cpu.",
+				InvolvedObject: types.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: name},
+			})
+		}
+	}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 1 {
+		t.Fatalf("Expected 1 hypothesis, got %+v", result.Hypotheses)
+	}
+	h := result.Hypotheses[0]
+	if h.Issue != "DataLoad job pods cannot be scheduled" || len(h.Evidence) != 3 {
+		t.Errorf("Expected the scheduling issue with phase, pod and event evidence, got %+v", h)
+	}
+}
+
+func TestAnalyze_DataLoadComplete(t *testing.T) {
+	ctx := scenario.New("mydata").DataLoad().Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 0 {
+		t.Errorf("Expected no hypotheses for a completed DataLoad, got %+v", result.Hypotheses)
+	}
+}
+
+func TestAnalyze_CacheRules(t *testing.T) {
+	cases := []struct {
+		name   string
+		ctx    types.DiagnosticContext
+		issues []string
+	}{
+		{
+			name:   "fully-cached",
+			ctx:    scenario.New("mydata").Cache("10Gi", "4Gi").DataLoad().Build(),
+			issues: nil,
+		},
+		{
+			name: "full-low-hit-ratio",
+			ctx:  scenario.New("mydata").Cache("4Gi", "10Gi").CachedPercent(40).CacheHitRatio(12).Build(),
+			issues: []string{
+				"Dataset cache is full and its hit ratio has collapsed",
+				"Dataset is larger than its cache capacity, so it can never be fully cached",
+			},
+		},
+		{
+			name:   "no-capacity",
+			ctx:    scenario.New("mydata").Cache("0", "10Gi").Build(),
+			issues: []string{"Dataset has no cache capacity, so nothing can be cached"},
+		},
+		{
+			name:   "loaded-nothing-cached",
+			ctx:    scenario.New("mydata").Cache("10Gi", "4Gi").CachedPercent(0).DataLoad().Build(),
+			issues: []string{"Dataset caches nothing although data was loaded"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Analyze(tc.ctx)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+			var issues []string
+			for _, h := range result.Hypotheses {
+				issues = append(issues, h.Issue)
+			}
+			if !reflect.DeepEqual(issues, tc.issues) {
+				t.Errorf("Expected issues %q, got %q", tc.issues, issues)
+			}
+		})
+	}
+}
+
+func TestAnalyze_TieredStoreMemExceedsLimit(t *testing.T) {
+	ctx := scenario.New("mydata").
+		Workers(2).
+		TieredStore(types.TieredStoreLevel{MediumType: "MEM", Path: "/dev/shm", Quota: "8Gi"}).
+		Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	for _, h := range result.Hypotheses {
+		if h.Component != "TieredStore" {
+			continue
+		}
+		if h.Confidence != types.ConfidenceEventAndStatus {
+			t.Errorf("Expected OOMKilled workers to corroborate the tier, got %v", h.Confidence)
+		}
+		want := "Runtime default/mydata tier 0 (MEM): quota 8.00GiB is not below the worker memory limit 4.00GiB"
+		if len(h.Evidence) != 3 || h.Evidence[0] != want {
+			t.Errorf("Expected the tier and 2 OOMKilled workers as evidence, got %v", h.Evidence)
+		}
+		return
+	}
+	t.Errorf("Expected a TieredStore hypothesis, got %+v", result.Hypotheses)
+}
+
+func TestAnalyze_TieredStoreMissingPath(t *testing.T) {
+	ctx := scenario.New("mydata").
+		TieredStore(
+			types.TieredStoreLevel{MediumType: "MEM", Path: "/dev/shm", Quota: "1Gi"},
+			types.TieredStoreLevel{MediumType: "SSD", Path: "/mnt/ssd0,/mnt/ssd1", Quota: "100Gi"},
+			types.TieredStoreLevel{MediumType: "HDD", Quota: "1Ti"},
+		).
+		Build()
+	pod := ctx.Graph.Pods["mydata-worker-0"]
+	ctx.Events = append(ctx.Events, types.Event{
+		Type:           "Warning",
+		Reason:         "FailedMount",
+		Message:        `MountVolume.SetUp failed for volume "ssd1" : hostPath type check failed: /mnt/ssd1 is not a directory`,
+		InvolvedObject: types.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name},
+	})
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 1 {
+		t.Fatalf("Expected 1 hypothesis, got %+v", result.Hypotheses)
+	}
+	h := result.Hypotheses[0]
+	want := []string{
+		"Runtime default/mydata tier 2 (HDD): no path configured",
+		`Runtime default/mydata tier 1 (SSD): hostPath missing on pod mydata-worker-0: MountVolume.SetUp failed for volume "ssd1" : hostPath type check failed: /mnt/ssd1 is not a directory`,
+	}
+	if !reflect.DeepEqual(h.Evidence, want) {
+		t.Errorf("Expected evidence %q, got %q", want, h.Evidence)
+	}
+}
+
+func TestAnalyze_TieredStoreVolumeAndRequest(t *testing.T) {
+	ctx := scenario.New("mydata").
+		WorkerMemoryRequest("2Gi").
+		TieredStore(
+			types.TieredStoreLevel{MediumType: "MEM", Path: "/dev/shm", Quota: "3Gi"},
+			types.TieredStoreLevel{MediumType: "SSD", VolumeType: "emptyDir", Path: "/mnt/ssd0", Quota: "100Gi"},
+			types.TieredStoreLevel{MediumType: "HDD", VolumeType: "nfs", Path: "/mnt/hdd0", Quota: "1Ti"},
+		).
+		Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 1 {
+		t.Fatalf("Expected 1 hypothesis, got %+v", result.Hypotheses)
+	}
+	want := []string{
+		"Runtime default/mydata tier 0 (MEM): quota 3.00GiB is not below the worker memory request 2.00GiB, so the scheduler reserves less than the ramdisk can use",
+		"Runtime default/mydata tier 1 (SSD): backed by an emptyDir on the node's root disk instead of a hostPath on the SSD",
+		`Runtime default/mydata tier 2 (HDD): unknown volumeType "nfs"`,
+	}
+	if got := result.Hypotheses[0].Evidence; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected evidence %q, got %q", want, got)
+	}
+}
+
+func TestAnalyze_UFSExceedsTieredStore(t *testing.T) {
+	ctx := scenario.New("mydata").Workers(2).Build()
+	dataset := ctx.Graph.Datasets["mydata"]
+	dataset.UfsTotal = "10Gi"
+	ctx.Graph.Datasets["mydata"] = dataset
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 1 {
+		t.Fatalf("Expected 1 hypothesis, got %+v", result.Hypotheses)
+	}
+	want := "Dataset default/mydata: UFS total 10.00GiB exceeds tiered-store capacity 4.00GiB"
+	if ev := result.Hypotheses[0].Evidence; len(ev) != 1 || ev[0] != want {
+		t.Errorf("Expected %q, got %v", want, ev)
+	}
+}
+
+func TestAnalyze_StorageRules(t *testing.T) {
+	withPVC := func(ctx types.DiagnosticContext, pvc types.PVCInfo) types.DiagnosticContext {
+		ctx.Graph.PVCs[pvc.Name] = pvc
+		return ctx
+	}
+
+	missingClass := withPVC(scenario.New("mydata").Build(), types.PVCInfo{
+		Name: "scratch", Namespace: "default", Status: "Pending", StorageClassName: "fast-ssd",
+	})
+	missingClass.Events = append(missingClass.Events, types.Event{
+		Type:           "Warning",
+		Reason:         "ProvisioningFailed",
+		Message:        `storageclass.storage.k8s.io "fast-ssd" not found`,
+		InvolvedObject: types.ObjectReference{Kind: index.KindPVC, Namespace: "default", Name: "scratch"},
+	})
+
+	accessMode := scenario.New("mydata").Build()
+	pvc := accessMode.Graph.PVCs["mydata"]
+	pvc.Status, pvc.AccessModes = "Pending", []string{"ReadWriteMany"}
+	accessMode.Graph.PVCs["mydata"] = pvc
+
+	released := scenario.New("mydata").Build()
+	pv := released.Graph.PVs["default-mydata"]
+	pv.Status = "Released"
+	released.Graph.PVs["default-mydata"] = pv
+	pvc = released.Graph.PVCs["mydata"]
+	pvc.Status = "Pending"
+	released.Graph.PVCs["mydata"] = pvc
+
+	cases := []struct {
+		name       string
+		ctx        types.DiagnosticContext
+		issue      string
+		confidence float64
+	}{
+		{"missing-storageclass", missingClass,
+			"PVC references a StorageClass that does not exist, so no volume is provisioned", types.ConfidenceEventAndStatus},
+		{"access-mode-mismatch", accessMode,
+			"PVC access modes are not offered by any matching PV, so the claim cannot bind", types.ConfidencePodStatusOnly},
+		{"released-pv", released,
+			"PersistentVolume is Released or Failed and cannot be bound again", types.ConfidencePodStatusOnly},
+		{"missing-fluid-pv", scenario.New("mydata").MissingPV().Build(),
+			"Fluid did not create the PersistentVolume for the Dataset, so its PVC cannot bind", types.ConfidencePodStatusOnly},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Analyze(tc.ctx)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+			var issues []string
+			for _, h := range result.Hypotheses {
+				issues = append(issues, h.Issue)
+				if h.Issue == tc.issue && h.Confidence != tc.confidence {
+					t.Errorf("Expected confidence %v, got %v", tc.confidence, h.Confidence)
+				}
+			}
+			if !slices.Contains(issues, tc.issue) {
+				t.Errorf("Expected %q among %q", tc.issue, issues)
+			}
+			if generic := "PVC is not bound due to storage provisioning failure"; slices.Contains(issues, generic) {
+				t.Errorf("Expected the specific storage rule to replace %q, got %q", generic, issues)
+			}
+		})
+	}
+}
+
+func TestAnalyze_ReleasedPVScope(t *testing.T) {
+	const issue = "PersistentVolume is Released or Failed and cannot be bound again"
+
+	unrelated := scenario.New("mydata").Workers(2).Build()
+	unrelated.Graph.PVs["pvc-3f2a9c"] = types.PVInfo{
+		Name: "pvc-3f2a9c", Status: "Released", StorageClassName: "standard",
+		ClaimRef: &types.ObjectReference{Kind: index.KindPVC, Namespace: "monitoring", Name: "prometheus-data"},
+	}
+
+	dataset := scenario.New("mydata").Workers(2).Build()
+	pv := dataset.Graph.PVs["default-mydata"]
+	pv.Status = "Released"
+	dataset.Graph.PVs["default-mydata"] = pv
+
+	for name, tc := range map[string]struct {
+		ctx  types.DiagnosticContext
+		want bool
+	}{
+		"unrelated PV":     {unrelated, false},
+		"Dataset's own PV": {dataset, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := Analyze(tc.ctx)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+			found := false
+			for _, h := range result.Hypotheses {
+				found = found || h.Issue == issue
+			}
+			if found != tc.want {
+				t.Errorf("Expected a Released PV hypothesis: %v, got %+v", tc.want, result.Hypotheses)
+			}
+		})
+	}
+}
+
+func TestAnalyze_StaticStorageClassNeedsNoObject(t *testing.T) {
+	ctx := scenario.New("mydata").Build()
+	ctx.Graph.StorageClasses = map[string]types.StorageClassInfo{}
+	ctx.Graph.PVCs["app-data"] = types.PVCInfo{
+		Name: "app-data", Namespace: "default", Status: "Pending", StorageClassName: "fluid",
+	}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	for _, h := range result.Hypotheses {
+		if strings.Contains(h.Issue, "StorageClass") {
+			t.Errorf("Expected the fluid class to be treated as static, got %+v", h)
+		}
+	}
+}
+
+func impactContext() types.DiagnosticContext {
+	return types.DiagnosticContext{
+		Graph: types.ResourceGraph{
+			Pods: map[string]types.PodInfo{
+				"reader-0": {Name: "reader-0", Namespace: "default", Status: "Pending", PVCs: []string{"a"},
+					OwnerReferences: []types.OwnerReference{{Kind: "StatefulSet", Name: "reader"}}},
+				"trainer-7c9d8-x2k4p": {Name: "trainer-7c9d8-x2k4p", Namespace: "default", Status: "Running", PVCs: []string{"b"},
+					OwnerReferences: []types.OwnerReference{{Kind: "ReplicaSet", Name: "trainer-7c9d8"}}},
+				"trainer-7c9d8-q8z2m": {Name: "trainer-7c9d8-q8z2m", Namespace: "default", Status: "Running", PVCs: []string{"b"},
+					OwnerReferences: []types.OwnerReference{{Kind: "ReplicaSet", Name: "trainer-7c9d8"}}},
+				"etl-28m4z": {Name: "etl-28m4z", Namespace: "default", Status: "Running", PVCs: []string{"b", "scratch"},
+					OwnerReferences: []types.OwnerReference{{Kind: "Job", Name: "etl"}}},
+			},
+			PVCs: map[string]types.PVCInfo{
+				"a": {Name: "a", Namespace: "default", Status: "Lost"},
+			},
+			Runtimes: map[string]types.RuntimeInfo{
+				"b": {Name: "b", Namespace: "default", Type: "Alluxio", MasterReplicas: 1},
+			},
+		},
+	}
+}
+
+func TestAnalyze_Impact(t *testing.T) {
+	result, err := Analyze(impactContext())
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 2 {
+		t.Fatalf("Expected 2 hypotheses, got %+v", result.Hypotheses)
+	}
+	if result.Hypotheses[0].Component != "Storage" {
+		t.Errorf("Expected confidence ranking to put Storage first, got %s", result.Hypotheses[0].Component)
+	}
+
+	master := result.Hypotheses[1]
+	want := &types.Impact{
+		Pods:       []string{"default/etl-28m4z", "default/trainer-7c9d8-q8z2m", "default/trainer-7c9d8-x2k4p"},
+		Namespaces: []string{"default"},
+		Workloads:  []string{"Deployment default/trainer", "Job default/etl"},
+	}
+	if !reflect.DeepEqual(master.Impact, want) {
+		t.Errorf("Expected impact %+v, got %+v", want, master.Impact)
+	}
+}
+
+func TestAnalyze_ImpactRanking(t *testing.T) {
+	result, err := Analyze(impactContext(), WithImpactRanking())
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 2 {
+		t.Fatalf("Expected 2 hypotheses, got %+v", result.Hypotheses)
+	}
+	if h := result.Hypotheses[0]; h.Component != "Master" || h.Rank != 1 {
+		t.Errorf("Expected the hypothesis affecting 2 workloads first, got %+v", h)
+	}
+}
+
+func TestAnalyze_ClusterWideHypothesisHasNoImpact(t *testing.T) {
+	ctx := scenario.New("mydata").AppPods(2).CrashLoopingController().Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	for _, h := range result.Hypotheses {
+		if h.Issue == "Fluid controller is unhealthy, so Dataset and Runtime status is not being reconciled" &&
+			(h.Impact != nil || len(h.Objects) != 0) {
+			t.Errorf("Expected the controller hypothesis to be cluster-wide, got %+v", h)
+		}
+	}
+}
+
+func TestAnalyze_EvictedWorkerBlamesDiskCache(t *testing.T) {
+	ctx := scenario.New("mydata").
+		Workers(2).
+		TieredStore(
+			types.TieredStoreLevel{MediumType: "MEM", Path: "/dev/shm", Quota: "1Gi"},
+			types.TieredStoreLevel{MediumType: "SSD", Path: "/var/lib/fluid/cache", Quota: "200Gi"},
+		).
+		EvictedWorkers(1).
+		Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	for _, h := range result.Hypotheses {
+		if h.Component != "Node" {
+			continue
+		}
+		if h.Severity != types.SeverityHigh || h.Confidence != types.ConfidenceEventAndStatus {
+			t.Errorf("Expected a corroborated high-severity eviction, got %+v", h)
+		}
+		if h.Issue != "Node evicted Fluid pods under DiskPressure; the Runtime's disk cache is a probable cause" {
+			t.Errorf("Unexpected issue %q", h.Issue)
+		}
+		want := []string{
+			"Node node-0: Condition DiskPressure=True, reason=KubeletHasDiskPressure",
+			"Pod default/mydata-worker-0 (worker): Evicted - The node was low on resource: ephemeral-storage.",
+			"Event on pod mydata-worker-0: Evicted - The node was low on resource: ephemeral-storage.",
+			"Runtime default/mydata tier 1 (SSD, /var/lib/fluid/cache): caches up to 200Gi per worker on node-local disk",
+		}
+		if !reflect.DeepEqual(h.Evidence, want) {
+			t.Errorf("Expected evidence %q, got %q", want, h.Evidence)
+		}
+		wantObjects := []types.ObjectReference{
+			{Kind: "Node", Name: "node-0"},
+			{Kind: "Dataset", Namespace: "default", Name: "mydata"},
+		}
+		if !reflect.DeepEqual(h.Objects, wantObjects) {
+			t.Errorf("Expected objects %+v, got %+v", wantObjects, h.Objects)
+		}
+		return
+	}
+	t.Errorf("Expected a Node hypothesis, got %+v", result.Hypotheses)
+}
+
+func TestAnalyze_NodePressureWithoutEviction(t *testing.T) {
+	ctx := scenario.New("mydata").Build()
+	node := ctx.Graph.Nodes["node-0"]
+	node.Conditions = []types.Condition{{Type: "MemoryPressure", Status: "True", Reason: "KubeletHasInsufficientMemory"}}
+	ctx.Graph.Nodes["node-0"] = node
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 1 {
+		t.Fatalf("Expected 1 hypothesis, got %+v", result.Hypotheses)
+	}
+	h := result.Hypotheses[0]
+	if h.Severity != types.SeverityMedium || h.Confidence != types.ConfidenceConditionOnly {
+		t.Errorf("Expected a medium-severity condition-only hypothesis, got %+v", h)
+	}
+	if h.Issue != "Node is under MemoryPressure, putting the Fluid pods on it at risk of eviction" {
+		t.Errorf("Unexpected issue %q", h.Issue)
+	}
+}
+
+func TestAnalyze_PressureOnNodeWithoutFluidPods(t *testing.T) {
+	ctx := scenario.New("mydata").Nodes(2).Build()
+	ctx.Graph.Nodes["node-9"] = types.NodeInfo{
+		Name:       "node-9",
+		Conditions: []types.Condition{{Type: "DiskPressure", Status: "True"}},
+	}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(result.Hypotheses) != 0 {
+		t.Errorf("Expected pressure on a node without Fluid pods to be ignored, got %+v", result.Hypotheses)
+	}
+}
+
+func TestAnalyze_WorkerNodeSelectorTypo(t *testing.T) {
+	ctx := scenario.New("mydata").
+		Nodes(2).
+		NodeLabels(map[string]string{"disktype": "ssd"}).
+		WorkerNodeSelector(map[string]string{"disktpye": "ssd"}).
+		Build()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	for _, h := range result.Hypotheses {
+		if h.Component != "Worker" {
+			continue
+		}
+		if h.Issue != "Worker pods cannot be scheduled: no node matches label disktpye" {
+			t.Errorf("Unexpected issue %q", h.Issue)
+		}
+		if h.Confidence != types.ConfidenceEventAndStatus {
+			t.Errorf("Expected node labels to corroborate the scheduler, got %v", h.Confidence)
+		}
+		want := "Runtime default/mydata worker nodeSelector disktpye=ssd: no node has label disktpye"
+		if n := len(h.Evidence); n != 2 || h.Evidence[n-1] != want {
+			t.Errorf("Expected the pending worker and %q as evidence, got %q", want, h.Evidence)
+		}
+		return
+	}
+	t.Errorf("Expected a Worker hypothesis, got %+v", result.Hypotheses)
+}
+
+func TestAnalyze_DatasetNodeAffinity(t *testing.T) {
+	tests := []struct {
+		name      string
+		terms     []types.NodeSelectorTerm
+		wantIssue string
+		wantLast  string
+	}{
+		{
+			name: "value typo",
+			terms: []types.NodeSelectorTerm{{MatchExpressions: []types.NodeSelectorRequirement{
+				{Key: "disktype", Operator: "In", Values: []string{"sdd"}},
+			}}},
+			wantIssue: "Worker pods cannot be scheduled: no node matches label disktype",
+			wantLast:  "Dataset default/mydata nodeAffinity term 0 disktype=sdd: no node matches; nodes have disktype=hdd,ssd",
+		},
+		{
+			name: "one satisfiable term",
+			terms: []types.NodeSelectorTerm{
+				{MatchExpressions: []types.NodeSelectorRequirement{{Key: "gpu", Operator: "Exists"}}},
+				{MatchExpressions: []types.NodeSelectorRequirement{{Key: "disktype", Operator: "In", Values: []string{"ssd"}}}},
+			},
+			wantIssue: "Worker pods cannot be scheduled: no node matches their node affinity/selector",
+		},
+		{
+			name: "no node has both",
+			terms: []types.NodeSelectorTerm{{MatchExpressions: []types.NodeSelectorRequirement{
+				{Key: "disktype", Operator: "In", Values: []string{"ssd"}},
+				{Key: "kubernetes.io/hostname", Operator: "In", Values: []string{"node-1"}},
+			}}},
+			wantIssue: "Worker pods cannot be scheduled: no node matches their node affinity/selector",
+			wantLast:  "No single node satisfies all worker node requirements together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Leave the worker Pending via a selector, then move the
+			// requirement to the Dataset.
+			ctx := scenario.New("mydata").
+				Nodes(2).
+				WorkerNodeSelector(map[string]string{"disktype": "nvme"}).
+				Build()
+			ctx.Graph.Nodes["node-0"].Labels["disktype"] = "ssd"
+			ctx.Graph.Nodes["node-1"].Labels["disktype"] = "hdd"
+			runtime := ctx.Graph.Runtimes["mydata"]
+			runtime.WorkerNodeSelector = nil
+			ctx.Graph.Runtimes["mydata"] = runtime
+			dataset := ctx.Graph.Datasets["mydata"]
+			dataset.NodeAffinity = tt.terms
+			ctx.Graph.Datasets["mydata"] = dataset
+
+			result, err := Analyze(ctx)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+			for _, h := range result.Hypotheses {
+				if h.Component != "Worker" {
+					continue
+				}
+				if h.Issue != tt.wantIssue {
+					t.Errorf("Expected issue %q, got %q", tt.wantIssue, h.Issue)
+				}
+				if last := h.Evidence[len(h.Evidence)-1]; tt.wantLast != "" && last != tt.wantLast {
+					t.Errorf("Expected last evidence %q, got %q", tt.wantLast, last)
+				}
+				return
+			}
+			t.Errorf("Expected a Worker hypothesis, got %+v", result.Hypotheses)
+		})
+	}
+}
+
+func TestAnalyze_VersionSkew(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          types.DiagnosticContext
+		wantSeverity int
+		wantIssue    string
+	}{
+		{
+			name:         "aligned",
+			ctx:          scenario.New("mydata").ControlPlane().FluidVersion("v1.0.3").Build(),
+			wantSeverity: 0,
+		},
+		{
+			name:         "CSI plugin a minor release behind",
+			ctx:          scenario.New("mydata").Nodes(2).ControlPlane().FluidVersion("v1.0.3").CSIPluginVersion("v0.9.3").Build(),
+			wantSeverity: types.SeverityHigh,
+			wantIssue:    "Fluid components run different releases: csi-nodeplugin-fluid out of line with v1.0.3",
+		},
+		{
+			name:         "patch skew",
+			ctx:          scenario.New("mydata").ControlPlane().FluidVersion("v1.0.3").CSIPluginVersion("v1.0.1").Build(),
+			wantSeverity: types.SeverityMedium,
+			wantIssue:    "Fluid components run different releases: csi-nodeplugin-fluid out of line with v1.0.3",
+		},
+		{
+			name:         "collector versioned independently",
+			ctx:          scenario.New("mydata").ControlPlane().FluidVersion("v1.0.3").CollectorVersion("v0.9.0").Build(),
+			wantSeverity: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Analyze(tt.ctx)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+			var got *types.Hypothesis
+			for i, h := range result.Hypotheses {
+				if strings.HasPrefix(h.Issue, "Fluid components run different releases") {
+					got = &result.Hypotheses[i]
+				}
+			}
+			switch {
+			case got == nil && tt.wantSeverity != 0:
+				t.Fatalf("Expected a version skew hypothesis, got %+v", result.Hypotheses)
+			case got != nil && tt.wantSeverity == 0:
+				t.Fatalf("Expected no version skew, got %+v", got)
+			case got != nil && (got.Severity != tt.wantSeverity || got.Issue != tt.wantIssue):
+				t.Errorf("Expected %q with severity %d, got %q with severity %d", tt.wantIssue, tt.wantSeverity, got.Issue, got.Severity)
+			}
+		})
+	}
+}
+
+func TestAnalyze_VersionSkewWithoutReportedRelease(t *testing.T) {
+	ctx := scenario.New("mydata").Nodes(3).ControlPlane().FluidVersion("v1.0.3").CSIPluginVersion("v0.9.3").Build()
+	ctx.Summary.FluidVersion = ""
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	for _, h := range result.Hypotheses {
+		if h.Component != "Controller" {
+			continue
+		}
+		// Three CSI pods on v0.9.3 tie with the controllers and webhook on
+		// v1.0.3; ties go to the newer release.
+		if h.Evidence[0] != "Fluid v1.0.3: run by 3 of 6 control-plane pods" {
+			t.Errorf("Expected the newer release to win the tie, got %q", h.Evidence[0])
+		}
+		return
+	}
+	t.Errorf("Expected a version skew hypothesis, got %+v", result.Hypotheses)
+}
+
+func TestAnalyze_FuseSidecar(t *testing.T) {
+	tests := []struct {
+		name           string
+		builder        *scenario.Builder
+		wantIssue      string
+		wantConfidence float64
+	}{
+		{
+			name:      "healthy",
+			builder:   scenario.New("mydata").AppPods(2).FuseSidecar(),
+			wantIssue: "",
+		},
+		{
+			name:           "not injected",
+			builder:        scenario.New("mydata").AppPods(2).FuseSidecarNotInjected(),
+			wantIssue:      "Pods requesting a Fuse sidecar were started without one, so the Dataset is not mounted in them",
+			wantConfidence: types.ConfidencePodStatusOnly,
+		},
+		{
+			name:           "crash-looping sidecar",
+			builder:        scenario.New("mydata").AppPods(2).CrashLoopingFuseSidecar(),
+			wantIssue:      "Injected Fuse sidecar is not running, so the application cannot read the Dataset",
+			wantConfidence: types.ConfidenceEventAndStatus,
+		},
+		{
+			name:           "startup race",
+			builder:        scenario.New("mydata").AppPods(1).FuseSidecarStartupRace(),
+			wantIssue:      "Application containers start before the Fuse sidecar has mounted the Dataset",
+			wantConfidence: types.ConfidenceLogMatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Analyze(tt.builder.Build())
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+			var sidecar []types.Hypothesis
+			for _, h := range result.Hypotheses {
+				if h.Component == "FuseSidecar" {
+					sidecar = append(sidecar, h)
+				}
+			}
+			if tt.wantIssue == "" {
+				if len(sidecar) != 0 {
+					t.Errorf("Expected no FuseSidecar hypotheses, got %+v", sidecar)
+				}
+				return
+			}
+			if len(sidecar) != 1 {
+				t.Fatalf("Expected 1 FuseSidecar hypothesis, got %+v", sidecar)
+			}
+			h := sidecar[0]
+			if h.Issue != tt.wantIssue || h.Confidence != tt.wantConfidence {
+				t.Errorf("Expected %q at %v, got %q at %v", tt.wantIssue, tt.wantConfidence, h.Issue, h.Confidence)
+			}
+			if h.Impact == nil || len(h.Impact.Pods) == 0 {
+				t.Errorf("Expected the application pods as impact, got %+v", h.Impact)
+			}
+		})
+	}
+}
+
+func TestAnalyze_FuseSidecarStartupOrderNeedsMountError(t *testing.T) {
+	unrelated := scenario.New("mydata").AppPods(1).FuseSidecarStartupRace().Build()
+	event := scenario.New("mydata").AppPods(1).FuseSidecarStartupRace().Build()
+	for name, pod := range unrelated.Graph.Pods {
+		if _, ok := unrelated.Logs[name]; ok {
+			unrelated.Logs[name] = "ERROR connection to postgres:5432 refused\nINFO Retrying"
+		}
+		if _, ok := event.Logs[name]; ok {
+			delete(event.Logs, name)
+			event.Events = append(event.Events, types.Event{
+				Type:           "Warning",
+				Reason:         "BackOff",
+				Message:        "open /data/mydata: transport endpoint is not connected",
+				InvolvedObject: types.ObjectReference{Kind: index.KindPod, Namespace: pod.Namespace, Name: name},
+			})
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		ctx        types.DiagnosticContext
+		confidence float64
+	}{
+		"unrelated logs": {unrelated, 0},
+		"mount event":    {event, types.ConfidenceEventAndStatus},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := Analyze(tc.ctx)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+			var got []types.Hypothesis
+			for _, h := range result.Hypotheses {
+				if h.Issue == "Application containers start before the Fuse sidecar has mounted the Dataset" {
+					got = append(got, h)
+				}
+			}
+			switch {
+			case tc.confidence == 0 && len(got) != 0:
+				t.Errorf("Expected no startup-order hypothesis for an unrelated restart, got %+v", got)
+			case tc.confidence != 0 && (len(got) != 1 || got[0].Confidence != tc.confidence):
+				t.Errorf("Expected a startup-order hypothesis at %v, got %+v", tc.confidence, got)
+			}
+		})
+	}
+}
+
+func TestAnalyze_FuseSidecarNotInjectedBlamesWebhook(t *testing.T) {
+	ctx := scenario.New("mydata").AppPods(1).FuseSidecarNotInjected().ControlPlane().Build()
+	for name := range ctx.Graph.ControlPlane {
+		if strings.HasPrefix(name, "fluid-webhook-") {
+			delete(ctx.Graph.ControlPlane, name)
+		}
+	}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	for _, h := range result.Hypotheses {
+		if h.Component != "FuseSidecar" {
+			continue
+		}
+		if last := h.Evidence[len(h.Evidence)-1]; !strings.HasPrefix(last, "Control plane: no webhook pods") {
+			t.Errorf("Expected the missing webhook as evidence, got %q", h.Evidence)
+		}
+		if h.Confidence != types.ConfidenceEventAndStatus {
+			t.Errorf("Expected the missing webhook to raise confidence, got %v", h.Confidence)
+		}
+		return
+	}
+	t.Errorf("Expected a FuseSidecar hypothesis, got %+v", result.Hypotheses)
+}
+
+func TestAnalyze_FuseSidecarStartupSuggestionFollowsClusterVersion(t *testing.T) {
+	for clusterVersion, want := range map[string]string{
+		"v1.30.2": "Kubernetes v1.30.2 supports native sidecar containers",
+		"v1.27.9": "Kubernetes v1.27.9 has no native sidecar containers",
+		"":        "Make the application wait for the mount",
+	} {
+		ctx := scenario.New("mydata").AppPods(1).FuseSidecarStartupRace().ClusterVersion(clusterVersion).Build()
+		result, err := Analyze(ctx)
+		if err != nil {
+			t.Fatalf("Analyze returned error: %v", err)
+		}
+		if len(result.Hypotheses) != 1 || !strings.HasPrefix(result.Hypotheses[0].Suggestion, want) {
+			t.Errorf("Cluster %q: expected a suggestion starting %q, got %+v", clusterVersion, want, result.Hypotheses)
+		}
+	}
+}
+
+// twoTenants has a failing Dataset in each of two namespaces.
+func twoTenants() types.DiagnosticContext {
+	return scenario.Merge(
+		scenario.New("sales").Namespace("team-a").Workers(2).OOMKilledWorkers(1).AppPods(1).Build(),
+		scenario.New("ads").Namespace("team-b").MissingPV().AppPods(1).Build(),
+	)
+}
+
+func TestAnalyze_Scope(t *testing.T) {
+	tests := []struct {
+		name      string
+		opt       Option
+		scope     string
+		namespace string
+	}{
+		{"dataset", WithDataset("team-a", "sales"), "dataset team-a/sales", "team-a"},
+		{"namespace", WithNamespace("team-b"), "namespace team-b", "team-b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Analyze(twoTenants(), tt.opt)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+			if result.Scope != tt.scope {
+				t.Errorf("Expected scope %q, got %q", tt.scope, result.Scope)
+			}
+			if len(result.Hypotheses) == 0 {
+				t.Fatal("Expected hypotheses within the scope")
+			}
+			for _, h := range result.Hypotheses {
+				for _, obj := range h.Objects {
+					if obj.Namespace != tt.namespace {
+						t.Errorf("%s: cites %s/%s outside the scope", h.Issue, obj.Namespace, obj.Name)
+					}
+				}
+				for _, e := range h.Evidence {
+					if strings.Contains(e, "team-a") && tt.namespace != "team-a" || strings.Contains(e, "team-b") && tt.namespace != "team-b" {
+						t.Errorf("%s: evidence from outside the scope: %s", h.Issue, e)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyze_ScopeUnknownDataset(t *testing.T) {
+	if _, err := Analyze(twoTenants(), WithDataset("team-b", "sales")); err == nil {
+		t.Error("Expected an error for a Dataset outside the context")
+	}
+}
+
+func TestAnalyze_ScopeKeepsInput(t *testing.T) {
+	ctx := twoTenants()
+	if _, err := Analyze(ctx, WithDataset("team-a", "sales")); err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if !reflect.DeepEqual(ctx, twoTenants()) {
+		t.Error("Expected scoping to leave the input context untouched")
+	}
+}
+
+func TestAnalyze_DatasetGrouping(t *testing.T) {
+	ctx := scenario.Merge(twoTenants(), scenario.New("reports").Namespace("team-a").Build())
+	ctx.Summary.ClusterVersion, ctx.Summary.FluidVersion = "v1.16.0", "v1.0.3"
+	result, err := Analyze(ctx, WithDatasetGrouping())
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	var got []string
+	for _, group := range result.Datasets {
+		got = append(got, group.Namespace+"/"+group.Name)
+		for i, h := range group.Hypotheses {
+			if h.Rank != i+1 {
+				t.Errorf("%s: expected rank %d, got %d for %s", group.Name, i+1, h.Rank, h.Issue)
+			}
+			if !concernsDataset(h, group.Namespace, group.Name) {
+				t.Errorf("%s: grouped a hypothesis about %+v", group.Name, h.Objects)
+			}
+		}
+	}
+	if want := []string{"team-a/reports", "team-a/sales", "team-b/ads"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected groups %v, got %v", want, got)
+	}
+	if n := len(result.Datasets[0].Hypotheses); n != 0 {
+		t.Errorf("Expected no hypotheses for the healthy Dataset, got %d", n)
+	}
+	if len(result.Datasets[1].Hypotheses) == 0 || len(result.Datasets[2].Hypotheses) == 0 {
+		t.Errorf("Expected hypotheses for both failing Datasets, got %+v", result.Datasets)
+	}
+
+	if len(result.Cluster) == 0 {
+		t.Fatal("Expected the unsupported cluster version in the cluster-wide group")
+	}
+	for i, h := range result.Cluster {
+		if len(h.Objects) != 0 || h.Rank != i+1 {
+			t.Errorf("Unexpected cluster-wide hypothesis %+v", h)
+		}
+	}
+
+	grouped := map[string]bool{}
+	for _, h := range result.Cluster {
+		grouped[h.Issue] = true
+	}
+	for _, group := range result.Datasets {
+		for _, h := range group.Hypotheses {
+			grouped[h.Issue] = true
+		}
+	}
+	for _, h := range result.Hypotheses {
+		if !grouped[h.Issue] {
+			t.Errorf("Expected %q in a group", h.Issue)
+		}
+	}
+}
+
+func TestAnalyze_NoGroupingByDefault(t *testing.T) {
+	result, err := Analyze(twoTenants())
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if result.Datasets != nil || result.Cluster != nil {
+		t.Errorf("Expected no groups without WithDatasetGrouping, got %+v %+v", result.Datasets, result.Cluster)
+	}
+}
+
+func TestAnalyze_HealthVerdict(t *testing.T) {
+	faulty := DefaultRulePacks()[0]
+	faulty.Rules = append([]Evaluator{Adapt(&panickingRule{phase: PhaseMatch})}, faulty.Rules...)
+
+	tests := []struct {
+		name string
+		ctx  types.DiagnosticContext
+		opts []Option
+		want types.Verdict
+	}{
+		{"healthy", scenario.New("mydata").ControlPlane().AppPods(1).Build(), nil, types.VerdictHealthy},
+		{"degraded", scenario.New("mydata").Workers(2).OOMKilledWorkers(1).Build(), nil, types.VerdictDegraded},
+		{"unavailable", scenario.New("mydata").CrashLoopingMasters(1).Build(), nil, types.VerdictUnavailable},
+		{"empty", types.DiagnosticContext{}, nil, types.VerdictInsufficientData},
+		{"dataset only", types.DiagnosticContext{Graph: types.ResourceGraph{
+			Datasets: map[string]types.DatasetInfo{"mydata": {Name: "mydata", Namespace: "default", Status: "Bound"}},
+		}}, nil, types.VerdictInsufficientData},
+		{"rules failed", scenario.New("mydata").Build(), []Option{WithRulePacks(faulty)}, types.VerdictInsufficientData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Analyze(tt.ctx, tt.opts...)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+			if result.Health.Verdict != tt.want {
+				t.Errorf("Expected verdict %s, got %s (hypotheses %+v)", tt.want, result.Health.Verdict, result.Hypotheses)
+			}
+		})
+	}
+}
+
+func TestAnalyze_HealthCoverage(t *testing.T) {
+	ctx := scenario.New("mydata").Nodes(3).Workers(2).DataLoad().Build()
+	ctx.Events = nil
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	inspected := result.Health.Coverage.Inspected
+	if inspected["Dataset"] != 1 || inspected["Node"] != 3 || inspected["DataLoad"] != 1 {
+		t.Errorf("Unexpected inspected counts %v", inspected)
+	}
+	if _, ok := inspected["Event"]; ok {
+		t.Errorf("Expected no Event count without events, got %v", inspected)
+	}
+	missing := result.Health.Coverage.Missing
+	if !slices.Contains(missing, "events") || !slices.Contains(missing, "graph.controlPlane") || slices.Contains(missing, "graph.datasets") {
+		t.Errorf("Unexpected missing sections %v", missing)
+	}
+}
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// Rule evaluation phases reported in types.RuleError. Evaluators fail in
+// PhaseEvaluate; rules wrapped by Adapt report PhaseMatch or PhaseHypothesis.
+const (
+	PhaseEvaluate   = "evaluate"
+	PhaseMatch      = "match"
+	PhaseHypothesis = "hypothesis"
+)
+
+// ruleOutcome is the result of evaluating a single rule.
+type ruleOutcome struct {
+	hypotheses []types.Hypothesis
+	err        *types.RuleError
+}
+
+// evaluateRules evaluates every rule and returns their outcomes indexed like
+// rules. Up to concurrency rules run at once; values below 2 evaluate
+// sequentially. It returns runCtx.Err() if runCtx ends before all rules ran.
+func evaluateRules(runCtx context.Context, rules []Evaluator, idx *index.Index, concurrency int) ([]ruleOutcome, error) {
+	outcomes := make([]ruleOutcome, len(rules))
+
+	if concurrency < 2 {
+		for i, rule := range rules {
+			if err := runCtx.Err(); err != nil {
+				return nil, err
+			}
+			outcomes[i].hypotheses, outcomes[i].err = evaluateRule(rule, idx)
+		}
+		return outcomes, nil
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, len(rules)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				outcomes[i].hypotheses, outcomes[i].err = evaluateRule(rules[i], idx)
+			}
+		}()
+	}
+
+feed:
+	for i := range rules {
+		select {
+		case <-runCtx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := runCtx.Err(); err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+// evaluateRule runs a single rule, converting a panic into a RuleError so
+// that one faulty rule cannot abort the whole analysis.
+func evaluateRule(rule Evaluator, idx *index.Index) (hypotheses []types.Hypothesis, ruleErr *types.RuleError) {
+	phase := PhaseEvaluate
+	defer func() {
+		if r := recover(); r != nil {
+			hypotheses = nil
+			ruleErr = &types.RuleError{
+				RuleID:  rule.ID(),
+				Phase:   phase,
+				Message: fmt.Sprintf("panic: %v", r),
+			}
+		}
+	}()
+
+	if adapter, ok := rule.(ruleAdapter); ok {
+		return adapter.evaluate(idx, &phase), nil
+	}
+	return rule.Evaluate(idx), nil
+}
@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// Golden scenarios live in testdata/golden/<scenario>/ as an input.json
+// DiagnosticContext and the expected.json DiagnosisResult it must produce.
+// Run `go test ./pkg/engine -run TestGolden -update` to (re)write expected.json.
+var updateGolden = flag.Bool("update", false, "rewrite golden expected.json files from current Analyze output")
+
+const goldenDir = "testdata/golden"
+
+func TestGolden(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join(goldenDir, "*", "input.json"))
+	if err != nil {
+		t.Fatalf("Failed to list golden scenarios: %v", err)
+	}
+	if len(dirs) == 0 {
+		t.Fatalf("No golden scenarios found under %s", goldenDir)
+	}
+
+	for _, inputPath := range dirs {
+		dir := filepath.Dir(inputPath)
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			runGoldenScenario(t, dir)
+		})
+	}
+}
+
+func runGoldenScenario(t *testing.T, dir string) {
+	t.Helper()
+
+	input, err := os.ReadFile(filepath.Join(dir, "input.json"))
+	if err != nil {
+		t.Fatalf("Failed to read input: %v", err)
+	}
+
+	var ctx types.DiagnosticContext
+	if err := json.Unmarshal(input, &ctx); err != nil {
+		t.Fatalf("Failed to parse input.json: %v", err)
+	}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	got, err := normalizeGolden(result)
+	if err != nil {
+		t.Fatalf("Failed to normalize result: %v", err)
+	}
+
+	expectedPath := filepath.Join(dir, "expected.json")
+	if *updateGolden {
+		if err := os.WriteFile(expectedPath, got, 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", expectedPath, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(expectedPath)
+	if os.IsNotExist(err) {
+		t.Fatalf("Missing %s; run `go test ./pkg/engine -run TestGolden -update` to create it", expectedPath)
+	}
+	if err != nil {
+		t.Fatalf("Failed to read expected output: %v", err)
+	}
+
+	if !bytes.Equal(bytes.TrimSpace(got), bytes.TrimSpace(want)) {
+		t.Errorf("Analyze output differs from %s (-expected +actual):\n%s",
+			expectedPath, lineDiff(string(want), string(got)))
+	}
+}
+
+// normalizeGolden renders a result as indented JSON without the fields that
+// legitimately change between runs.
+func normalizeGolden(result types.DiagnosisResult) ([]byte, error) {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	delete(doc, "generatedAt")
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// lineDiff returns a line-based diff of want and got, marking removed lines
+// with "-" and added lines with "+". Unchanged lines further than
+// diffContext lines from a change are elided.
+func lineDiff(want, got string) string {
+	const diffContext = 3
+
+	a := strings.Split(strings.TrimRight(want, "\n"), "\n")
+	b := strings.Split(strings.TrimRight(got, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type diffLine struct {
+		op   byte
+		text string
+	}
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+
+	keep := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for c := max(0, k-diffContext); c <= min(len(lines)-1, k+diffContext); c++ {
+			keep[c] = true
+		}
+	}
+
+	var sb strings.Builder
+	elided := false
+	for k, l := range lines {
+		if !keep[k] {
+			if !elided {
+				sb.WriteString("  ...\n")
+				elided = true
+			}
+			continue
+		}
+		elided = false
+		fmt.Fprintf(&sb, "%c %s\n", l.op, l.text)
+	}
+	return sb.String()
+}
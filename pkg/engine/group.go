@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// groupByDataset splits ranked hypotheses by the Datasets they name. A
+// hypothesis naming several Datasets appears in each of their groups; one
+// naming none, or only Nodes, is cluster-wide. Every Dataset in the context
+// gets a group, and the ranking order is kept with ranks renumbered per group.
+func groupByDataset(idx *index.Index, hypotheses []types.Hypothesis) ([]types.DatasetResult, []types.Hypothesis) {
+	datasets := idx.Context().Graph.Datasets
+	var groups []types.DatasetResult
+	for _, name := range idx.DatasetNames() {
+		groups = append(groups, types.DatasetResult{
+			Namespace:  datasets[name].Namespace,
+			Name:       name,
+			Hypotheses: []types.Hypothesis{},
+		})
+	}
+	slices.SortFunc(groups, func(a, b types.DatasetResult) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	var cluster []types.Hypothesis
+	for _, h := range hypotheses {
+		placed := false
+		for i := range groups {
+			if concernsDataset(h, groups[i].Namespace, groups[i].Name) {
+				h.Rank = len(groups[i].Hypotheses) + 1
+				groups[i].Hypotheses = append(groups[i].Hypotheses, h)
+				placed = true
+			}
+		}
+		if !placed {
+			h.Rank = len(cluster) + 1
+			cluster = append(cluster, h)
+		}
+	}
+	return groups, cluster
+}
+
+// concernsDataset reports whether h names the Dataset namespace/name, its
+// Runtime or its PVC; Fluid names all three alike.
+func concernsDataset(h types.Hypothesis, namespace, name string) bool {
+	for _, obj := range h.Objects {
+		switch obj.Kind {
+		case index.KindDataset, index.KindRuntime, index.KindPVC:
+			if obj.Namespace == namespace && obj.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
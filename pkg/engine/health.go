@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"slices"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// assessHealth gives the overall verdict on ctx from the hypotheses found,
+// together with what the rules were able to inspect.
+func assessHealth(ctx types.DiagnosticContext, hypotheses []types.Hypothesis, ruleErrors []types.RuleError) types.Health {
+	health := types.Health{Verdict: types.VerdictHealthy, Coverage: coverageOf(ctx)}
+	for _, h := range hypotheses {
+		if h.Severity == types.SeverityCritical {
+			health.Verdict = types.VerdictUnavailable
+			return health
+		}
+		health.Verdict = types.VerdictDegraded
+	}
+	// Finding nothing only means healthy if the rules could look
+	if len(hypotheses) == 0 && (len(ruleErrors) > 0 || slices.ContainsFunc(health.Coverage.Missing, func(section string) bool {
+		return slices.Contains(requiredSections, section)
+	})) {
+		health.Verdict = types.VerdictInsufficientData
+	}
+	return health
+}
+
+// requiredSections must be present for a context without findings to be
+// called healthy. Most rules start from the Datasets and follow them to the
+// Runtime, its pods and their nodes; events, logs and the control plane only
+// corroborate and may legitimately be empty.
+var requiredSections = []string{"graph.datasets", "graph.runtimes", "graph.pods", "graph.nodes"}
+
+// coverageOf counts the objects in ctx by kind and lists the sections that
+// are missing. PVs and StorageClasses are only missing when not collected;
+// an empty collection is a valid observation.
+func coverageOf(ctx types.DiagnosticContext) types.Coverage {
+	g := ctx.Graph
+	inspected := map[string]int{
+		index.KindDataset: len(g.Datasets),
+		index.KindRuntime: len(g.Runtimes),
+		index.KindPod:     len(g.Pods) + len(g.ControlPlane),
+		index.KindPVC:     len(g.PVCs),
+		index.KindPV:      len(g.PVs),
+		"StorageClass":    len(g.StorageClasses),
+		index.KindNode:    len(g.Nodes),
+		"Event":           len(ctx.Events),
+	}
+	for _, op := range g.DataOperations {
+		inspected[op.Kind]++
+	}
+	for kind, n := range inspected {
+		if n == 0 {
+			delete(inspected, kind)
+		}
+	}
+
+	var missing []string
+	for _, section := range []struct {
+		name    string
+		missing bool
+	}{
+		{"summary.clusterVersion", ctx.Summary.ClusterVersion == ""},
+		{"graph.nodes", len(g.Nodes) == 0},
+		{"graph.pods", len(g.Pods) == 0},
+		{"graph.pvcs", len(g.PVCs) == 0},
+		{"graph.datasets", len(g.Datasets) == 0},
+		{"graph.runtimes", len(g.Runtimes) == 0},
+		{"graph.pvs", g.PVs == nil},
+		{"graph.storageClasses", g.StorageClasses == nil},
+		{"graph.controlPlane", len(g.ControlPlane) == 0},
+		{"events", len(ctx.Events) == 0},
+		{"logs", len(ctx.Logs) == 0},
+	} {
+		if section.missing {
+			missing = append(missing, section.name)
+		}
+	}
+	return types.Coverage{Inspected: inspected, Missing: missing}
+}
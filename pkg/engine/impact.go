@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// annotateImpact sets the blast radius of every hypothesis from the objects
+// it names.
+func annotateImpact(idx *index.Index, hypotheses []types.Hypothesis) {
+	for i := range hypotheses {
+		hypotheses[i].Impact = impactOf(idx, hypotheses[i].Objects)
+	}
+}
+
+// impactOf returns the application pods affected by objects. A Dataset or
+// Runtime affects the pods mounting the PVC Fluid names after it, a PVC the
+// pods mounting it, and a Node the pods on it that mount any Fluid PVC.
+func impactOf(idx *index.Index, objects []types.ObjectReference) *types.Impact {
+	pods := map[string]types.PodInfo{}
+	add := func(candidates []types.PodInfo) {
+		for _, pod := range candidates {
+			if idx.Role(pod.Name) == roles.Unknown {
+				pods[pod.Namespace+"/"+pod.Name] = pod
+			}
+		}
+	}
+
+	for _, obj := range objects {
+		switch obj.Kind {
+		case index.KindDataset, index.KindRuntime, index.KindPVC:
+			add(idx.PodsUsingPVC(obj.Namespace, obj.Name))
+		case index.KindNode:
+			for _, pod := range idx.PodsOnNode(obj.Name) {
+				if mountsDataset(idx, pod) {
+					add([]types.PodInfo{pod})
+				}
+			}
+		}
+	}
+	if len(pods) == 0 {
+		return nil
+	}
+
+	impact := &types.Impact{}
+	for _, key := range slices.Sorted(maps.Keys(pods)) {
+		pod := pods[key]
+		impact.Pods = append(impact.Pods, key)
+		if !slices.Contains(impact.Namespaces, pod.Namespace) {
+			impact.Namespaces = append(impact.Namespaces, pod.Namespace)
+		}
+		if w := workloadOf(pod); !slices.Contains(impact.Workloads, w) {
+			impact.Workloads = append(impact.Workloads, w)
+		}
+	}
+	slices.Sort(impact.Namespaces)
+	slices.Sort(impact.Workloads)
+	return impact
+}
+
+// mountsDataset reports whether pod mounts the PVC of a Dataset.
+func mountsDataset(idx *index.Index, pod types.PodInfo) bool {
+	for _, claim := range pod.PVCs {
+		if dataset, ok := idx.Context().Graph.Datasets[claim]; ok && dataset.Namespace == pod.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// workloadOf names the workload that owns pod, resolving ReplicaSets to the
+// Deployment they were created for.
+func workloadOf(pod types.PodInfo) string {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "ReplicaSet" {
+			if i := strings.LastIndex(owner.Name, "-"); i > 0 {
+				return "Deployment " + pod.Namespace + "/" + owner.Name[:i]
+			}
+		}
+		return owner.Kind + " " + pod.Namespace + "/" + owner.Name
+	}
+	return "Pod " + pod.Namespace + "/" + pod.Name
+}
+
+// impactSize is the number of workloads a hypothesis affects.
+func impactSize(h types.Hypothesis) int {
+	if h.Impact == nil {
+		return 0
+	}
+	return len(h.Impact.Workloads)
+}
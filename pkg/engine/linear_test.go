@@ -0,0 +1,400 @@
+package engine
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// The rules below are the implementations from before the index was
+// introduced, kept verbatim apart from their names as the linear-scan
+// baseline for BenchmarkRules_Linear: every rule walks the raw context's
+// maps and events on its own.
+
+// linearFuseUnschedulable detects Fuse pods that are unschedulable due to node taints/tolerations.
+type linearFuseUnschedulable struct{}
+
+func (r *linearFuseUnschedulable) ID() string {
+	return "fuse-unschedulable"
+}
+
+func (r *linearFuseUnschedulable) Match(ctx types.DiagnosticContext) bool {
+	// Check for fuse pods in pending state with scheduling issues
+	for _, pod := range ctx.Graph.Pods {
+		if !linearIsFusePod(pod) {
+			continue
+		}
+		if pod.Status == "Pending" {
+			for _, cond := range pod.Conditions {
+				if cond.Type == "PodScheduled" && cond.Status == "False" {
+					return true
+				}
+			}
+		}
+	}
+
+	// Check for related events
+	for _, event := range ctx.Events {
+		if event.Type == "Warning" && strings.Contains(event.Reason, "FailedScheduling") {
+			if linearIsFuseEvent(event) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (r *linearFuseUnschedulable) Hypothesis(ctx types.DiagnosticContext) types.Hypothesis {
+	var evidence []string
+	confidence := types.ConfidenceConditionOnly
+
+	// Gather evidence from pods
+	for _, name := range slices.Sorted(maps.Keys(ctx.Graph.Pods)) {
+		pod := ctx.Graph.Pods[name]
+		if !linearIsFusePod(pod) {
+			continue
+		}
+		if pod.Status == "Pending" {
+			for _, cond := range pod.Conditions {
+				if cond.Type == "PodScheduled" && cond.Status == "False" {
+					evidence = append(evidence, fmt.Sprintf("Pod %s/%s: PodScheduled=False, reason=%s",
+						pod.Namespace, name, cond.Reason))
+					if cond.Reason != "" {
+						confidence = types.ConfidenceEventAndStatus
+					}
+				}
+			}
+		}
+	}
+
+	// Gather evidence from events
+	for _, event := range ctx.Events {
+		if event.Type == "Warning" && strings.Contains(event.Reason, "FailedScheduling") {
+			if linearIsFuseEvent(event) {
+				evidence = append(evidence, fmt.Sprintf("Event: %s - %s", event.Reason, event.Message))
+				confidence = types.ConfidenceEventAndStatus
+			}
+		}
+	}
+
+	return types.Hypothesis{
+		Confidence: confidence,
+		Component:  "Fuse",
+		Issue:      "Fuse pod cannot be scheduled due to node taints or missing tolerations",
+		Evidence:   evidence,
+		Suggestion: "Check node taints and ensure Fuse pods have appropriate tolerations. Verify node selectors match available nodes.",
+	}
+}
+
+func linearIsFusePod(pod types.PodInfo) bool {
+	// Check labels for fuse identification
+	if role, ok := pod.Labels["role"]; ok && role == "fuse" {
+		return true
+	}
+	if _, ok := pod.Labels["fluid.io/fuse"]; ok {
+		return true
+	}
+	// Check owner references
+	for _, owner := range pod.OwnerReferences {
+		if strings.Contains(strings.ToLower(owner.Name), "fuse") {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(pod.Name), "fuse")
+}
+
+func linearIsFuseEvent(event types.Event) bool {
+	return strings.Contains(strings.ToLower(event.InvolvedObject.Name), "fuse")
+}
+
+// linearRuntimePartiallyReady detects runtimes that are only partially ready due to dependency failures.
+type linearRuntimePartiallyReady struct{}
+
+func (r *linearRuntimePartiallyReady) ID() string {
+	return "runtime-partially-ready"
+}
+
+func (r *linearRuntimePartiallyReady) Match(ctx types.DiagnosticContext) bool {
+	for _, runtime := range ctx.Graph.Runtimes {
+		// Check if master or worker replicas are not fully ready
+		if runtime.MasterReplicas > 0 && runtime.MasterReady < runtime.MasterReplicas {
+			return true
+		}
+		if runtime.WorkerReplicas > 0 && runtime.WorkerReady < runtime.WorkerReplicas {
+			return true
+		}
+		// Check for non-ready conditions
+		for _, cond := range runtime.Conditions {
+			if cond.Status == "False" && cond.Type == "Ready" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *linearRuntimePartiallyReady) Hypothesis(ctx types.DiagnosticContext) types.Hypothesis {
+	var evidence []string
+	confidence := types.ConfidenceConditionOnly
+
+	for _, name := range slices.Sorted(maps.Keys(ctx.Graph.Runtimes)) {
+		runtime := ctx.Graph.Runtimes[name]
+		if runtime.MasterReplicas > 0 && runtime.MasterReady < runtime.MasterReplicas {
+			evidence = append(evidence, fmt.Sprintf("Runtime %s/%s: Master %d/%d ready",
+				runtime.Namespace, name, runtime.MasterReady, runtime.MasterReplicas))
+			confidence = types.ConfidencePodStatusOnly
+		}
+		if runtime.WorkerReplicas > 0 && runtime.WorkerReady < runtime.WorkerReplicas {
+			evidence = append(evidence, fmt.Sprintf("Runtime %s/%s: Worker %d/%d ready",
+				runtime.Namespace, name, runtime.WorkerReady, runtime.WorkerReplicas))
+			confidence = types.ConfidencePodStatusOnly
+		}
+		for _, cond := range runtime.Conditions {
+			if cond.Status == "False" {
+				evidence = append(evidence, fmt.Sprintf("Runtime %s/%s: Condition %s=%s, reason=%s",
+					runtime.Namespace, name, cond.Type, cond.Status, cond.Reason))
+				if cond.Reason != "" {
+					confidence = types.ConfidenceEventAndStatus
+				}
+			}
+		}
+	}
+
+	return types.Hypothesis{
+		Confidence: confidence,
+		Component:  "Runtime",
+		Issue:      "Runtime is only partially ready, indicating dependency or configuration failure",
+		Evidence:   evidence,
+		Suggestion: "Check runtime pod logs for errors. Verify storage backend connectivity and credentials. Ensure all required dependencies are available.",
+	}
+}
+
+// linearPVCUnbound detects PVCs that are not bound due to storage provisioning issues.
+type linearPVCUnbound struct{}
+
+func (r *linearPVCUnbound) ID() string {
+	return "pvc-unbound"
+}
+
+func (r *linearPVCUnbound) Match(ctx types.DiagnosticContext) bool {
+	for _, pvc := range ctx.Graph.PVCs {
+		if pvc.Status == "Pending" || pvc.Status == "Lost" {
+			return true
+		}
+	}
+
+	// Check for provisioning failure events
+	for _, event := range ctx.Events {
+		if event.Type == "Warning" && event.InvolvedObject.Kind == "PersistentVolumeClaim" {
+			if strings.Contains(event.Reason, "ProvisioningFailed") ||
+				strings.Contains(event.Reason, "FailedBinding") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (r *linearPVCUnbound) Hypothesis(ctx types.DiagnosticContext) types.Hypothesis {
+	var evidence []string
+	confidence := types.ConfidenceConditionOnly
+
+	for _, name := range slices.Sorted(maps.Keys(ctx.Graph.PVCs)) {
+		pvc := ctx.Graph.PVCs[name]
+		if pvc.Status == "Pending" {
+			evidence = append(evidence, fmt.Sprintf("PVC %s/%s: Status=Pending",
+				pvc.Namespace, name))
+			confidence = types.ConfidencePodStatusOnly
+		}
+		if pvc.Status == "Lost" {
+			evidence = append(evidence, fmt.Sprintf("PVC %s/%s: Status=Lost",
+				pvc.Namespace, name))
+			confidence = types.ConfidenceEventAndStatus
+		}
+	}
+
+	// Gather evidence from events
+	for _, event := range ctx.Events {
+		if event.Type == "Warning" && event.InvolvedObject.Kind == "PersistentVolumeClaim" {
+			if strings.Contains(event.Reason, "ProvisioningFailed") ||
+				strings.Contains(event.Reason, "FailedBinding") {
+				evidence = append(evidence, fmt.Sprintf("Event on PVC %s: %s - %s",
+					event.InvolvedObject.Name, event.Reason, event.Message))
+				confidence = types.ConfidenceEventAndStatus
+			}
+		}
+	}
+
+	return types.Hypothesis{
+		Confidence: confidence,
+		Component:  "Storage",
+		Issue:      "PVC is not bound due to storage provisioning failure",
+		Evidence:   evidence,
+		Suggestion: "Check storage class configuration and provisioner status. Verify storage backend has available capacity.",
+	}
+}
+
+// linearDatasetNotBound detects Datasets that are not bound due to missing Runtime.
+type linearDatasetNotBound struct{}
+
+func (r *linearDatasetNotBound) ID() string {
+	return "dataset-not-bound"
+}
+
+func (r *linearDatasetNotBound) Match(ctx types.DiagnosticContext) bool {
+	for _, dataset := range ctx.Graph.Datasets {
+		if dataset.Status == "NotBound" || dataset.Status == "" {
+			return true
+		}
+		for _, cond := range dataset.Conditions {
+			if cond.Type == "Ready" && cond.Status == "False" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *linearDatasetNotBound) Hypothesis(ctx types.DiagnosticContext) types.Hypothesis {
+	var evidence []string
+	confidence := types.ConfidenceConditionOnly
+
+	for _, name := range slices.Sorted(maps.Keys(ctx.Graph.Datasets)) {
+		dataset := ctx.Graph.Datasets[name]
+		if dataset.Status == "NotBound" || dataset.Status == "" {
+			statusStr := dataset.Status
+			if statusStr == "" {
+				statusStr = "<empty>"
+			}
+			evidence = append(evidence, fmt.Sprintf("Dataset %s/%s: Status=%s",
+				dataset.Namespace, name, statusStr))
+			confidence = types.ConfidencePodStatusOnly
+		}
+		for _, cond := range dataset.Conditions {
+			if cond.Type == "Ready" && cond.Status == "False" {
+				evidence = append(evidence, fmt.Sprintf("Dataset %s/%s: Condition Ready=%s, reason=%s",
+					dataset.Namespace, name, cond.Status, cond.Reason))
+				if cond.Reason != "" {
+					confidence = types.ConfidenceEventAndStatus
+				}
+			}
+		}
+	}
+
+	return types.Hypothesis{
+		Confidence: confidence,
+		Component:  "Dataset",
+		Issue:      "Dataset is not bound, likely due to missing or failed Runtime",
+		Evidence:   evidence,
+		Suggestion: "Ensure a Runtime (e.g., AlluxioRuntime, JuiceFSRuntime) is created for this Dataset. Check Runtime status for failures.",
+	}
+}
+
+// linearWorkerPendingMemory detects worker pods pending due to insufficient memory.
+type linearWorkerPendingMemory struct{}
+
+func (r *linearWorkerPendingMemory) ID() string {
+	return "worker-pending-memory"
+}
+
+func (r *linearWorkerPendingMemory) Match(ctx types.DiagnosticContext) bool {
+	// Check for worker pods in pending state
+	for _, pod := range ctx.Graph.Pods {
+		if !linearIsWorkerPod(pod) {
+			continue
+		}
+		if pod.Status == "Pending" {
+			// Check for memory-related scheduling failures
+			for _, cond := range pod.Conditions {
+				if cond.Type == "PodScheduled" && cond.Status == "False" {
+					if strings.Contains(strings.ToLower(cond.Message), "memory") ||
+						strings.Contains(strings.ToLower(cond.Message), "insufficient") {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	// Check events for memory issues
+	for _, event := range ctx.Events {
+		if event.Type == "Warning" && event.Reason == "FailedScheduling" {
+			if linearIsWorkerEvent(event) &&
+				strings.Contains(strings.ToLower(event.Message), "memory") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (r *linearWorkerPendingMemory) Hypothesis(ctx types.DiagnosticContext) types.Hypothesis {
+	var evidence []string
+	confidence := types.ConfidenceConditionOnly
+
+	// Gather evidence from pods
+	for _, name := range slices.Sorted(maps.Keys(ctx.Graph.Pods)) {
+		pod := ctx.Graph.Pods[name]
+		if !linearIsWorkerPod(pod) {
+			continue
+		}
+		if pod.Status == "Pending" {
+			for _, cond := range pod.Conditions {
+				if cond.Type == "PodScheduled" && cond.Status == "False" {
+					if strings.Contains(strings.ToLower(cond.Message), "memory") ||
+						strings.Contains(strings.ToLower(cond.Message), "insufficient") {
+						evidence = append(evidence, fmt.Sprintf("Pod %s/%s: %s",
+							pod.Namespace, name, cond.Message))
+						confidence = types.ConfidenceEventAndStatus
+					}
+				}
+			}
+		}
+	}
+
+	// Gather evidence from events
+	for _, event := range ctx.Events {
+		if event.Type == "Warning" && event.Reason == "FailedScheduling" {
+			if linearIsWorkerEvent(event) &&
+				strings.Contains(strings.ToLower(event.Message), "memory") {
+				evidence = append(evidence, fmt.Sprintf("Event: %s - %s", event.Reason, event.Message))
+				confidence = types.ConfidenceEventAndStatus
+			}
+		}
+	}
+
+	return types.Hypothesis{
+		Confidence: confidence,
+		Component:  "Worker",
+		Issue:      "Worker pod cannot be scheduled due to insufficient memory",
+		Evidence:   evidence,
+		Suggestion: "Reduce worker memory requests, add nodes with more memory, or scale down other workloads to free resources.",
+	}
+}
+
+func linearIsWorkerPod(pod types.PodInfo) bool {
+	// Check labels for worker identification
+	if role, ok := pod.Labels["role"]; ok && role == "worker" {
+		return true
+	}
+	if _, ok := pod.Labels["fluid.io/worker"]; ok {
+		return true
+	}
+	// Check owner references
+	for _, owner := range pod.OwnerReferences {
+		if strings.Contains(strings.ToLower(owner.Name), "worker") {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(pod.Name), "worker")
+}
+
+func linearIsWorkerEvent(event types.Event) bool {
+	return strings.Contains(strings.ToLower(event.InvolvedObject.Name), "worker")
+}
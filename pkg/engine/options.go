@@ -0,0 +1,105 @@
+package engine
+
+import "github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+
+// Option configures a single Analyze call.
+type Option func(*options)
+
+type options struct {
+	packs       []RulePack
+	errorPolicy ErrorPolicy
+	concurrency int
+	classifier  *roles.Classifier
+	rankImpact  bool
+	scope       *scope
+	groupByData bool
+}
+
+// ErrorPolicy controls how Analyze reacts to a rule that fails.
+type ErrorPolicy int
+
+const (
+	// ContinueOnRuleError records the failure in DiagnosisResult.RuleErrors
+	// and returns the hypotheses of the remaining rules.
+	ContinueOnRuleError ErrorPolicy = iota
+
+	// FailOnRuleError aborts the analysis and returns the types.RuleError.
+	FailOnRuleError
+)
+
+func defaultOptions() options {
+	return options{
+		packs:      DefaultRulePacks(),
+		classifier: roles.Default,
+	}
+}
+
+// WithRulePacks replaces the built-in rule packs. Packs are tried in order
+// and the first one compatible with the context is used.
+func WithRulePacks(packs ...RulePack) Option {
+	return func(o *options) {
+		o.packs = packs
+	}
+}
+
+// WithErrorPolicy sets how rule failures are handled. The default is
+// ContinueOnRuleError.
+func WithErrorPolicy(policy ErrorPolicy) Option {
+	return func(o *options) {
+		o.errorPolicy = policy
+	}
+}
+
+// WithConcurrency evaluates up to n rules in parallel. Results are merged in
+// rule order, so the output matches sequential evaluation. Values below 2
+// keep the default sequential behaviour.
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// WithRoleClassifier sets the classifier used to decide which Fluid
+// component each pod belongs to. The default is roles.Default.
+func WithRoleClassifier(c *roles.Classifier) Option {
+	return func(o *options) {
+		o.classifier = c
+	}
+}
+
+// WithImpactRanking ranks hypotheses by the number of workloads they affect
+// before confidence, so the problems hurting the most users come first.
+func WithImpactRanking() Option {
+	return func(o *options) {
+		o.rankImpact = true
+	}
+}
+
+// WithDatasetGrouping additionally reports the hypotheses per Dataset, each
+// group ranked on its own, plus a cluster-wide group, so a bundle covering a
+// whole cluster does not interleave unrelated Datasets. The flat ranking in
+// Hypotheses is unchanged.
+func WithDatasetGrouping() Option {
+	return func(o *options) {
+		o.groupByData = true
+	}
+}
+
+// WithNamespace restricts the analysis to the objects of one namespace, so a
+// multi-tenant bundle can be diagnosed one tenant at a time. Nodes, the
+// control plane and other cluster-scoped objects stay visible.
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.scope = &scope{namespace: namespace}
+	}
+}
+
+// WithDataset restricts the analysis to the subgraph reachable from one
+// Dataset: its Runtime and their pods, its PVC and PV, the pods mounting it
+// and its data operations. Analyze fails if the Dataset is not in the
+// context.
+func WithDataset(namespace, name string) Option {
+	return func(o *options) {
+		o.scope = &scope{namespace: namespace, dataset: name}
+	}
+}
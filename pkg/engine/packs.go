@@ -0,0 +1,167 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/rules"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/version"
+)
+
+// RulePack is a named, versioned bundle of rules together with the
+// environments it is known to be valid for.
+type RulePack struct {
+	Name          string
+	Version       string
+	Compatibility Compatibility
+	Rules         []Evaluator
+}
+
+// Compatibility describes the environments a RulePack supports.
+// Empty fields place no restriction.
+type Compatibility struct {
+	// CollectorVersions constrains Metadata.CollectorVersion, e.g. ">=0.9.0 <1.2.0",
+	// and so the schema of the collected context.
+	CollectorVersions string
+
+	// FluidVersions constrains Summary.FluidVersion, the installed Fluid
+	// release, e.g. ">=1.0.0".
+	FluidVersions string
+
+	// KubernetesVersions constrains Summary.ClusterVersion, e.g. ">=1.20".
+	KubernetesVersions string
+
+	// RuntimeTypes lists the supported RuntimeInfo.Type values (case-insensitive).
+	RuntimeTypes []string
+}
+
+// Supports reports whether the pack can be applied to the given context.
+// Versions that are missing or unparseable in the context never exclude a
+// pack, since the engine must not guess at data it was not given.
+// An error is returned if the pack's own constraints are malformed.
+func (p RulePack) Supports(ctx types.DiagnosticContext) (bool, error) {
+	c := p.Compatibility
+
+	ok, err := versionSupported(c.CollectorVersions, ctx.Metadata.CollectorVersion)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	ok, err = versionSupported(c.FluidVersions, ctx.Summary.FluidVersion)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	ok, err = versionSupported(c.KubernetesVersions, ctx.Summary.ClusterVersion)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if len(c.RuntimeTypes) > 0 {
+		for _, runtime := range ctx.Graph.Runtimes {
+			if runtime.Type != "" && !containsFold(c.RuntimeTypes, runtime.Type) {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// DefaultRulePacks returns the rule packs built into the engine, in
+// selection order.
+func DefaultRulePacks() []RulePack {
+	return []RulePack{
+		{
+			Name:    "core",
+			Version: "v1.0.0",
+			Rules: []Evaluator{
+				&rules.FuseUnschedulableRule{},
+				&rules.FuseSidecarNotInjectedRule{},
+				&rules.FuseSidecarCrashRule{},
+				&rules.FuseSidecarStartupOrderRule{},
+				&rules.WorkerPendingMemoryRule{},
+				&rules.NodeSelectorMismatchRule{},
+				&rules.RuntimePartiallyReadyRule{},
+				&rules.MasterNotReadyRule{},
+				&rules.PVCUnboundRule{},
+				&rules.StorageClassMissingRule{},
+				&rules.AccessModeMismatchRule{},
+				&rules.PVReleasedRule{},
+				&rules.FluidPVMissingRule{},
+				&rules.DatasetNotBoundRule{},
+				&rules.ControllerUnhealthyRule{},
+				&rules.StaleStatusRule{},
+				&rules.WebhookUnavailableRule{},
+				&rules.DataOperationFailedRule{},
+				&rules.DataOperationStuckRule{},
+				&rules.CacheFullRule{},
+				&rules.CacheNoProgressRule{},
+				&rules.UFSExceedsCacheRule{},
+				&rules.TieredStoreMisconfiguredRule{},
+				&rules.NodePressureRule{},
+				&rules.UnsupportedClusterVersionRule{},
+				&rules.VersionSkewRule{},
+			},
+		},
+	}
+}
+
+// SelectRulePack returns the first pack in packs that supports ctx.
+func SelectRulePack(packs []RulePack, ctx types.DiagnosticContext) (RulePack, error) {
+	for _, pack := range packs {
+		ok, err := pack.Supports(ctx)
+		if err != nil {
+			return RulePack{}, fmt.Errorf("rule pack %s@%s: %w", pack.Name, pack.Version, err)
+		}
+		if ok {
+			return pack, nil
+		}
+	}
+	return RulePack{}, fmt.Errorf("no rule pack supports collector version %q, Fluid version %q, cluster version %q",
+		ctx.Metadata.CollectorVersion, ctx.Summary.FluidVersion, ctx.Summary.ClusterVersion)
+}
+
+// applicableRules drops the VersionedEvaluators in rules that do not support
+// the context's cluster version.
+func applicableRules(rules []Evaluator, ctx types.DiagnosticContext) ([]Evaluator, error) {
+	var out []Evaluator
+	for _, rule := range rules {
+		if versioned, ok := rule.(VersionedEvaluator); ok {
+			ok, err := versionSupported(versioned.KubernetesVersions(), ctx.Summary.ClusterVersion)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %w", rule.ID(), err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		out = append(out, rule)
+	}
+	return out, nil
+}
+
+func versionSupported(constraint, raw string) (bool, error) {
+	if constraint == "" {
+		return true, nil
+	}
+	c, err := version.ParseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	v, err := version.Parse(raw)
+	if err != nil {
+		return true, nil
+	}
+	return c.Check(v), nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+package engine
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/scenario"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// The tests in this file check the invariants from PHASE0_DESIGN.md against
+// arbitrary inputs: Analyze never panics, never mutates its input, ranks
+// hypotheses 1..N, keeps confidences within [0,1], cites evidence for every
+// hypothesis and is deterministic.
+
+func FuzzAnalyze(f *testing.F) {
+	inputs, _ := filepath.Glob(filepath.Join(goldenDir, "*", "input.json"))
+	for _, path := range inputs {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatalf("Failed to read seed %s: %v", path, err)
+		}
+		f.Add(data)
+	}
+	for _, sc := range propertyScenarios() {
+		data, err := json.Marshal(sc.ctx)
+		if err != nil {
+			f.Fatalf("Failed to marshal seed: %v", err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var ctx types.DiagnosticContext
+		if err := json.Unmarshal(data, &ctx); err != nil {
+			t.Skip()
+		}
+		checkInvariants(t, ctx)
+	})
+}
+
+func FuzzScenario(f *testing.F) {
+	f.Add(uint8(3), uint8(1), uint8(0), uint8(3), uint8(1), uint8(1), uint8(1), false, false)
+	f.Add(uint8(1), uint8(0), uint8(0), uint8(0), uint8(0), uint8(0), uint8(0), true, false)
+	f.Add(uint8(10), uint8(3), uint8(2), uint8(20), uint8(10), uint8(5), uint8(10), false, true)
+
+	f.Fuzz(func(t *testing.T, nodes, masters, crashing, workers, oom, memory, tainted uint8, noRuntime, crashingController bool) {
+		b := scenario.New("mydata").
+			Nodes(int(nodes % 32)).
+			Masters(int(masters % 4)).
+			CrashLoopingMasters(int(crashing % 4)).
+			Workers(int(workers % 64)).
+			OOMKilledWorkers(int(oom % 64)).
+			MemoryPendingWorkers(int(memory % 64)).
+			TaintedNodes(int(tainted % 32))
+		if noRuntime {
+			b.WithoutRuntime()
+		}
+		if crashingController {
+			b.CrashLoopingController()
+		}
+		checkInvariants(t, b.Build())
+	})
+}
+
+func TestAnalyze_Invariants(t *testing.T) {
+	for _, sc := range propertyScenarios() {
+		t.Run(sc.name, func(t *testing.T) {
+			checkInvariants(t, sc.ctx)
+		})
+	}
+}
+
+func TestAnalyze_MapInsertionOrder(t *testing.T) {
+	ctx := scenario.New("mydata").
+		Nodes(5).
+		Workers(8).
+		TaintedNodes(2).
+		OOMKilledWorkers(2).
+		MemoryPendingWorkers(2).
+		NodeLabels(map[string]string{"disktype": "ssd", "zone": "a"}).
+		ControlPlane().
+		AppPods(2).
+		FuseSidecar().
+		OOMKilledDataLoad().
+		Build()
+	ctx.Graph.Runtimes["other"] = types.RuntimeInfo{
+		Name: "other", Namespace: "default", WorkerReplicas: 2, WorkerReady: 1,
+		Conditions: []types.Condition{{Type: "Ready", Status: "False", Reason: "WorkerNotReady"}},
+	}
+
+	g := ctx.Graph
+	for section, n := range map[string]int{
+		"nodes": len(g.Nodes), "pods": len(g.Pods), "pvcs": len(g.PVCs), "datasets": len(g.Datasets),
+		"runtimes": len(g.Runtimes), "pvs": len(g.PVs), "storageClasses": len(g.StorageClasses),
+		"controlPlane": len(g.ControlPlane), "dataOperations": len(g.DataOperations), "logs": len(ctx.Logs),
+	} {
+		if n == 0 {
+			t.Fatalf("Expected the context to populate %s", section)
+		}
+	}
+
+	want := analyzeNormalized(t, ctx)
+	for i := 0; i < 20; i++ {
+		got := analyzeNormalized(t, reinsert(ctx, i%2 == 0))
+		if !reflect.DeepEqual(want, got) {
+			t.Fatalf("Output depends on map insertion order:\nwant %+v\ngot  %+v", want, got)
+		}
+	}
+}
+
+type namedContext struct {
+	name string
+	ctx  types.DiagnosticContext
+}
+
+func propertyScenarios() []namedContext {
+	return []namedContext{
+		{"empty", types.DiagnosticContext{}},
+		{"healthy", scenario.New("mydata").Build()},
+		{"no-runtime", scenario.New("mydata").WithoutRuntime().Build()},
+		{"tainted-node", scenario.New("mydata").Nodes(3).Workers(3).TaintedNodes(1).Build()},
+		{"worker-failures", scenario.New("mydata").Nodes(3).Workers(6).OOMKilledWorkers(2).MemoryPendingWorkers(2).Build()},
+		{"all-tainted", scenario.New("mydata").Nodes(2).Masters(3).TaintedNodes(2).Build()},
+		{"crashing-master", scenario.New("mydata").Masters(3).CrashLoopingMasters(2).Build()},
+		{"control-plane", scenario.New("mydata").Nodes(2).ControlPlane().Build()},
+		{"crashing-controller", scenario.New("mydata").Workers(2).CrashLoopingController().Build()},
+		{"failed-dataload", scenario.New("mydata").Workers(2).OOMKilledDataLoad().Build()},
+		{"restarted-dataload", scenario.New("mydata").Workers(2).RestartedDataLoad().Build()},
+		{"pending-dataload", scenario.New("mydata").WithoutRuntime().DataLoad().Build()},
+		{"missing-pv", scenario.New("mydata").MissingPV().ControlPlane().Build()},
+		{"app-pods", scenario.New("mydata").Nodes(2).TaintedNodes(1).AppPods(3).Build()},
+		{"mem-tier-oom", scenario.New("mydata").Workers(2).WorkerMemoryLimit("1Gi").Build()},
+		{"full-cache", scenario.New("mydata").Cache("4Gi", "10Gi").CachedPercent(40).CacheHitRatio(12).Build()},
+		{"evicted-workers", scenario.New("mydata").Nodes(2).Workers(2).EvictedWorkers(1).Build()},
+		{"selector-typo", scenario.New("mydata").Nodes(2).WorkerNodeSelector(map[string]string{"disktpye": "ssd"}).Build()},
+		{"partial-upgrade", scenario.New("mydata").Nodes(2).ControlPlane().FluidVersion("v1.0.3").CSIPluginVersion("v0.9.3").Build()},
+		{"crashing-sidecar", scenario.New("mydata").AppPods(2).CrashLoopingFuseSidecar().Build()},
+		{"sidecar-startup-race", scenario.New("mydata").AppPods(1).FuseSidecarStartupRace().Build()},
+	}
+}
+
+// checkInvariants runs Analyze on ctx and fails t if any design invariant is
+// violated.
+func checkInvariants(t *testing.T, ctx types.DiagnosticContext) {
+	t.Helper()
+
+	before, err := json.Marshal(ctx)
+	if err != nil {
+		t.Fatalf("Failed to marshal input: %v", err)
+	}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	after, err := json.Marshal(ctx)
+	if err != nil {
+		t.Fatalf("Failed to marshal input: %v", err)
+	}
+	if string(before) != string(after) {
+		t.Fatal("Analyze mutated its input")
+	}
+
+	for _, e := range result.RuleErrors {
+		t.Errorf("Built-in rule failed: %v", e)
+	}
+
+	for i, h := range result.Hypotheses {
+		if h.Rank != i+1 {
+			t.Errorf("Hypothesis %d has rank %d, want %d", i, h.Rank, i+1)
+		}
+		if h.Confidence < 0 || h.Confidence > 1 {
+			t.Errorf("Hypothesis %q has confidence %f outside [0,1]", h.Issue, h.Confidence)
+		}
+		if len(h.Evidence) == 0 {
+			t.Errorf("Hypothesis %q has no evidence", h.Issue)
+		}
+		if h.Impact != nil && (len(h.Impact.Pods) == 0 || len(h.Impact.Workloads) == 0) {
+			t.Errorf("Hypothesis %q has an empty impact", h.Issue)
+		}
+	}
+
+	// A verdict of Healthy or InsufficientData means nothing was found
+	if quiet := result.Health.Verdict == types.VerdictHealthy || result.Health.Verdict == types.VerdictInsufficientData; quiet != (len(result.Hypotheses) == 0) {
+		t.Errorf("Verdict %s with %d hypotheses", result.Health.Verdict, len(result.Hypotheses))
+	}
+
+	again, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error on second run: %v", err)
+	}
+	result.GeneratedAt, again.GeneratedAt = time.Time{}, time.Time{}
+	if !reflect.DeepEqual(result, again) {
+		t.Fatalf("Analyze is not deterministic:\nfirst  %+v\nsecond %+v", result, again)
+	}
+}
+
+func analyzeNormalized(t *testing.T, ctx types.DiagnosticContext) types.DiagnosisResult {
+	t.Helper()
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	result.GeneratedAt = time.Time{}
+	return result
+}
+
+// reinsert copies every map of ctx, including the maps nested in graph
+// objects, inserting keys in ascending or descending order.
+func reinsert(ctx types.DiagnosticContext, descending bool) types.DiagnosticContext {
+	g := &ctx.Graph
+	g.Nodes = reinsertEach(g.Nodes, descending, func(n types.NodeInfo) types.NodeInfo {
+		n.Labels = reinsertMap(n.Labels, descending)
+		n.Allocatable = reinsertMap(n.Allocatable, descending)
+		n.Capacity = reinsertMap(n.Capacity, descending)
+		return n
+	})
+	pod := func(p types.PodInfo) types.PodInfo {
+		p.Labels = reinsertMap(p.Labels, descending)
+		p.Annotations = reinsertMap(p.Annotations, descending)
+		return p
+	}
+	g.Pods = reinsertEach(g.Pods, descending, pod)
+	g.ControlPlane = reinsertEach(g.ControlPlane, descending, pod)
+	g.PVCs = reinsertEach(g.PVCs, descending, func(p types.PVCInfo) types.PVCInfo {
+		p.Selector = reinsertMap(p.Selector, descending)
+		return p
+	})
+	g.PVs = reinsertEach(g.PVs, descending, func(pv types.PVInfo) types.PVInfo {
+		pv.Labels = reinsertMap(pv.Labels, descending)
+		return pv
+	})
+	g.Datasets = reinsertMap(g.Datasets, descending)
+	g.Runtimes = reinsertEach(g.Runtimes, descending, func(r types.RuntimeInfo) types.RuntimeInfo {
+		r.WorkerRequests = reinsertMap(r.WorkerRequests, descending)
+		r.WorkerLimits = reinsertMap(r.WorkerLimits, descending)
+		r.WorkerNodeSelector = reinsertMap(r.WorkerNodeSelector, descending)
+		return r
+	})
+	g.StorageClasses = reinsertMap(g.StorageClasses, descending)
+	g.DataOperations = reinsertMap(g.DataOperations, descending)
+	ctx.Logs = reinsertMap(ctx.Logs, descending)
+	return ctx
+}
+
+// reinsertEach is like reinsertMap but also rebuilds each value with f.
+func reinsertEach[V any](m map[string]V, descending bool, f func(V) V) map[string]V {
+	out := reinsertMap(m, descending)
+	for k, v := range out {
+		out[k] = f(v)
+	}
+	return out
+}
+
+func reinsertMap[V any](m map[string]V, descending bool) map[string]V {
+	if m == nil {
+		return nil
+	}
+	keys := slices.Sorted(maps.Keys(m))
+	if descending {
+		slices.Reverse(keys)
+	}
+	out := make(map[string]V, len(m))
+	for _, k := range keys {
+		out[k] = m[k]
+	}
+	return out
+}
@@ -1,9 +1,38 @@
 package engine
 
-import "github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+import (
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
 
-// Rule defines the interface for deterministic reasoning rules.
-// Each rule inspects the DiagnosticContext and produces a Hypothesis if matched.
+// Evaluator defines the interface for deterministic reasoning rules.
+// Each rule inspects the indexed DiagnosticContext in a single pass and
+// returns zero or more hypotheses, so the predicate that decides whether the
+// rule applies is the same code that collects its evidence.
+type Evaluator interface {
+	// ID returns a unique identifier for this rule.
+	ID() string
+
+	// Evaluate returns the hypotheses supported by the context, or nil.
+	// Every returned hypothesis must cite at least one piece of evidence.
+	Evaluate(idx *index.Index) []types.Hypothesis
+}
+
+// VersionedEvaluator is implemented by evaluators that only apply to some
+// Kubernetes releases, e.g. because they depend on a scheduler message or a
+// feature that changed. The engine skips them when Summary.ClusterVersion is
+// known and does not satisfy KubernetesVersions; rules that merely adapt to
+// the version can use index.Index.ClusterVersion instead. It is an extension
+// point for custom rule packs: none of the built-in rules is limited this way.
+type VersionedEvaluator interface {
+	Evaluator
+
+	// KubernetesVersions returns a version.Constraint, e.g. ">=1.29".
+	KubernetesVersions() string
+}
+
+// Rule is the original two-phase rule interface. New rules should implement
+// Evaluator; existing rules can be used in a RulePack through Adapt.
 type Rule interface {
 	// ID returns a unique identifier for this rule.
 	ID() string
@@ -15,3 +44,53 @@ type Rule interface {
 	// This should only be called if Match returns true.
 	Hypothesis(ctx types.DiagnosticContext) types.Hypothesis
 }
+
+// IndexedRule is implemented by two-phase rules that can evaluate against a
+// precomputed index.Index instead of rescanning the DiagnosticContext.
+// Adapt calls these methods in preference to Match and Hypothesis.
+type IndexedRule interface {
+	Rule
+
+	// MatchIndexed is the indexed equivalent of Match.
+	MatchIndexed(idx *index.Index) bool
+
+	// HypothesisIndexed is the indexed equivalent of Hypothesis.
+	HypothesisIndexed(idx *index.Index) types.Hypothesis
+}
+
+// Adapt wraps a two-phase Rule (or IndexedRule) as an Evaluator.
+func Adapt(rule Rule) Evaluator {
+	return ruleAdapter{rule: rule}
+}
+
+type ruleAdapter struct {
+	rule Rule
+}
+
+func (a ruleAdapter) ID() string {
+	return a.rule.ID()
+}
+
+func (a ruleAdapter) Evaluate(idx *index.Index) []types.Hypothesis {
+	var phase string
+	return a.evaluate(idx, &phase)
+}
+
+// evaluate runs Match then Hypothesis, recording the current phase so the
+// engine can attribute a panic to the right one.
+func (a ruleAdapter) evaluate(idx *index.Index, phase *string) []types.Hypothesis {
+	*phase = PhaseMatch
+	if indexed, ok := a.rule.(IndexedRule); ok {
+		if !indexed.MatchIndexed(idx) {
+			return nil
+		}
+		*phase = PhaseHypothesis
+		return []types.Hypothesis{indexed.HypothesisIndexed(idx)}
+	}
+
+	if !a.rule.Match(idx.Context()) {
+		return nil
+	}
+	*phase = PhaseHypothesis
+	return []types.Hypothesis{a.rule.Hypothesis(idx.Context())}
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// scope restricts an analysis to one namespace or one Dataset.
+type scope struct {
+	namespace string
+	dataset   string // empty for the whole namespace
+}
+
+func (s scope) String() string {
+	if s.dataset == "" {
+		return "namespace " + s.namespace
+	}
+	return "dataset " + s.namespace + "/" + s.dataset
+}
+
+// applyScope returns the part of ctx that s can reach. Namespaced objects
+// outside the scope are dropped together with their events and logs.
+// Cluster-scoped objects the scope may depend on are kept: all nodes, the
+// control plane, StorageClasses and PVs unclaimed or claimed within s.
+// ctx itself is not modified.
+func applyScope(ctx types.DiagnosticContext, s scope) (types.DiagnosticContext, error) {
+	g := ctx.Graph
+	if s.dataset != "" {
+		if d, ok := g.Datasets[s.dataset]; !ok || d.Namespace != s.namespace {
+			return types.DiagnosticContext{}, fmt.Errorf("scope: %s not found in context", s)
+		}
+	}
+
+	out := ctx
+	out.Summary.Namespace = s.namespace
+	out.Graph = types.ResourceGraph{
+		Nodes:          g.Nodes,
+		StorageClasses: g.StorageClasses,
+		ControlPlane:   g.ControlPlane,
+		Datasets:       filterMap(g.Datasets, func(d types.DatasetInfo) bool { return s.covers(d.Namespace, d.Name) }),
+		Runtimes:       filterMap(g.Runtimes, func(r types.RuntimeInfo) bool { return s.covers(r.Namespace, r.Name) }),
+		PVCs:           filterMap(g.PVCs, func(p types.PVCInfo) bool { return s.covers(p.Namespace, p.Name) }),
+		DataOperations: filterMap(g.DataOperations, func(op types.DataOperationInfo) bool { return s.covers(op.Namespace, op.Dataset) }),
+	}
+	if g.PVs != nil {
+		out.Graph.PVs = filterMap(g.PVs, func(pv types.PVInfo) bool {
+			return pv.ClaimRef == nil || s.covers(pv.ClaimRef.Namespace, pv.ClaimRef.Name)
+		})
+	}
+	out.Graph.Pods = filterMap(g.Pods, func(pod types.PodInfo) bool { return s.coversPod(pod, out.Graph) })
+
+	// Events on kept objects, their owners and cluster-scoped objects
+	kept := map[index.ObjectKey]bool{}
+	for _, pod := range out.Graph.Pods {
+		kept[index.ObjectKey{Kind: index.KindPod, Namespace: pod.Namespace, Name: pod.Name}] = true
+		for _, owner := range pod.OwnerReferences {
+			kept[index.ObjectKey{Kind: owner.Kind, Namespace: pod.Namespace, Name: owner.Name}] = true
+		}
+	}
+	for _, pod := range g.ControlPlane {
+		kept[index.ObjectKey{Kind: index.KindPod, Namespace: pod.Namespace, Name: pod.Name}] = true
+	}
+	for _, pvc := range out.Graph.PVCs {
+		kept[index.ObjectKey{Kind: index.KindPVC, Namespace: pvc.Namespace, Name: pvc.Name}] = true
+	}
+	for _, d := range out.Graph.Datasets {
+		kept[index.ObjectKey{Kind: index.KindDataset, Namespace: d.Namespace, Name: d.Name}] = true
+	}
+	for _, r := range out.Graph.Runtimes {
+		kept[index.ObjectKey{Kind: index.KindRuntime, Namespace: r.Namespace, Name: r.Name}] = true
+	}
+	for _, op := range out.Graph.DataOperations {
+		kept[index.ObjectKey{Kind: op.Kind, Namespace: op.Namespace, Name: op.Name}] = true
+	}
+	out.Events = nil
+	for _, event := range ctx.Events {
+		obj := event.InvolvedObject
+		if obj.Namespace == "" || kept[index.ObjectKey(obj)] || (s.dataset == "" && obj.Namespace == s.namespace) {
+			out.Events = append(out.Events, event)
+		}
+	}
+
+	out.Logs = map[string]string{}
+	for name, log := range ctx.Logs {
+		if _, ok := out.Graph.Pods[name]; ok {
+			out.Logs[name] = log
+		} else if _, ok := g.ControlPlane[name]; ok {
+			out.Logs[name] = log
+		}
+	}
+
+	return out, nil
+}
+
+// covers reports whether the object namespace/name, or the Dataset of that
+// name, is in scope.
+func (s scope) covers(namespace, name string) bool {
+	return namespace == s.namespace && (s.dataset == "" || name == s.dataset)
+}
+
+// coversPod reports whether pod is in scope: in a namespace scope every pod
+// of the namespace, in a Dataset scope the Runtime's pods, the pods mounting
+// its PVC and the job pods of its data operations.
+func (s scope) coversPod(pod types.PodInfo, scoped types.ResourceGraph) bool {
+	if pod.Namespace != s.namespace {
+		return false
+	}
+	if s.dataset == "" {
+		return true
+	}
+	if pod.Labels["release"] == s.dataset || pod.Labels["targetDataset"] == s.dataset ||
+		slices.Contains(pod.PVCs, s.dataset) {
+		return true
+	}
+	for _, owner := range pod.OwnerReferences {
+		switch owner.Name {
+		case s.dataset + "-master", s.dataset + "-worker", s.dataset + "-fuse":
+			return true
+		}
+		for _, op := range scoped.DataOperations {
+			if owner.Kind == index.KindJob && op.Job != "" && owner.Name == op.Job {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func filterMap[V any](m map[string]V, keep func(V) bool) map[string]V {
+	if m == nil {
+		return nil
+	}
+	out := map[string]V{}
+	for k, v := range m {
+		if keep(v) {
+			out[k] = v
+		}
+	}
+	return out
+}
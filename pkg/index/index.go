@@ -0,0 +1,398 @@
+// Package index provides a precomputed, read-only view of a
+// DiagnosticContext. It is built once per analysis so that rules can look up
+// related objects directly instead of rescanning every pod and event.
+//
+// Slices returned by an Index are shared and must not be modified. All
+// lookups return objects in a deterministic order.
+package index
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/version"
+)
+
+// Object kinds used for events and conditions.
+const (
+	KindPod     = "Pod"
+	KindPVC     = "PersistentVolumeClaim"
+	KindPV      = "PersistentVolume"
+	KindDataset = "Dataset"
+	KindRuntime = "Runtime"
+	KindNode    = "Node"
+	KindJob     = "Job"
+
+	KindDataLoad    = "DataLoad"
+	KindDataMigrate = "DataMigrate"
+	KindDataBackup  = "DataBackup"
+	KindDataProcess = "DataProcess"
+)
+
+// FuseInjectLabel asks Fluid's webhook to inject Fuse sidecars into a pod
+// (serverless mode). It is honoured as a label or an annotation.
+const FuseInjectLabel = "serverless.fluid.io/inject"
+
+// FuseInjectionRequested reports whether pod asks for Fuse sidecar injection.
+func FuseInjectionRequested(pod types.PodInfo) bool {
+	return pod.Labels[FuseInjectLabel] == "true" || pod.Annotations[FuseInjectLabel] == "true"
+}
+
+// IsFuseSidecar reports whether a container is a Fuse sidecar injected by
+// Fluid, named "fluid-fuse" or "fluid-fuse-<n>".
+func IsFuseSidecar(container string) bool {
+	return container == "fluid-fuse" || strings.HasPrefix(container, "fluid-fuse-")
+}
+
+// ObjectKey identifies an object by kind, namespace and name.
+type ObjectKey struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// ConditionRef is a condition together with the object that reports it.
+type ConditionRef struct {
+	Object    ObjectKey
+	Condition types.Condition
+}
+
+// Namespace lists the names of the graph objects in one namespace.
+type Namespace struct {
+	Pods     []string
+	PVCs     []string
+	Datasets []string
+	Runtimes []string
+
+	DataOperations []string
+}
+
+// Index is a read-only view of a DiagnosticContext.
+type Index struct {
+	ctx types.DiagnosticContext
+
+	clusterVersion    version.Version
+	hasClusterVersion bool
+
+	podNames     []string
+	pvcNames     []string
+	datasetNames []string
+	runtimeNames []string
+	nodeNames    []string
+	opNames      []string
+	pvNames      []string
+	classNames   []string
+	controlNames []string
+
+	eventsByObject map[ObjectKey][]types.Event
+	eventsByReason map[string][]types.Event
+
+	classifier  *roles.Classifier
+	podRoles    map[string]roles.Classification
+	podsByRole  map[roles.Role][]types.PodInfo
+	podsByOwner map[types.OwnerReference][]types.PodInfo
+	podsByNode  map[string][]types.PodInfo
+	podsByPVC   map[ObjectKey][]types.PodInfo
+
+	controlPlane []types.PodInfo
+	sidecarPods  []types.PodInfo
+	opsByDataset map[ObjectKey][]types.DataOperationInfo
+
+	namespaces map[string]*Namespace
+	conditions map[string][]ConditionRef
+}
+
+// Build indexes ctx, classifying pods with roles.Default. The context is not
+// copied; it must not be modified while the Index is in use.
+func Build(ctx types.DiagnosticContext) *Index {
+	return BuildWithClassifier(ctx, roles.Default)
+}
+
+// BuildWithClassifier indexes ctx, classifying pods with classifier.
+func BuildWithClassifier(ctx types.DiagnosticContext, classifier *roles.Classifier) *Index {
+	idx := &Index{
+		ctx:            ctx,
+		classifier:     classifier,
+		podNames:       slices.Sorted(maps.Keys(ctx.Graph.Pods)),
+		pvcNames:       slices.Sorted(maps.Keys(ctx.Graph.PVCs)),
+		datasetNames:   slices.Sorted(maps.Keys(ctx.Graph.Datasets)),
+		runtimeNames:   slices.Sorted(maps.Keys(ctx.Graph.Runtimes)),
+		nodeNames:      slices.Sorted(maps.Keys(ctx.Graph.Nodes)),
+		opNames:        slices.Sorted(maps.Keys(ctx.Graph.DataOperations)),
+		pvNames:        slices.Sorted(maps.Keys(ctx.Graph.PVs)),
+		classNames:     slices.Sorted(maps.Keys(ctx.Graph.StorageClasses)),
+		controlNames:   slices.Sorted(maps.Keys(ctx.Graph.ControlPlane)),
+		eventsByObject: map[ObjectKey][]types.Event{},
+		eventsByReason: map[string][]types.Event{},
+		podRoles:       map[string]roles.Classification{},
+		podsByRole:     map[roles.Role][]types.PodInfo{},
+		podsByOwner:    map[types.OwnerReference][]types.PodInfo{},
+		podsByNode:     map[string][]types.PodInfo{},
+		podsByPVC:      map[ObjectKey][]types.PodInfo{},
+		namespaces:     map[string]*Namespace{},
+		conditions:     map[string][]ConditionRef{},
+		opsByDataset:   map[ObjectKey][]types.DataOperationInfo{},
+	}
+
+	if v, err := version.Parse(ctx.Summary.ClusterVersion); err == nil {
+		idx.clusterVersion, idx.hasClusterVersion = v, true
+	}
+
+	for _, event := range ctx.Events {
+		key := ObjectKey(event.InvolvedObject)
+		idx.eventsByObject[key] = append(idx.eventsByObject[key], event)
+		idx.eventsByReason[event.Reason] = append(idx.eventsByReason[event.Reason], event)
+	}
+
+	for _, name := range idx.podNames {
+		pod := ctx.Graph.Pods[name]
+		idx.addPod(name, pod)
+		if FuseInjectionRequested(pod) || slices.ContainsFunc(pod.ContainerStatuses, func(cs types.ContainerStatus) bool {
+			return IsFuseSidecar(cs.Name)
+		}) {
+			idx.sidecarPods = append(idx.sidecarPods, pod)
+		}
+	}
+	for _, name := range idx.controlNames {
+		idx.addPod(name, ctx.Graph.ControlPlane[name])
+		idx.controlPlane = append(idx.controlPlane, ctx.Graph.ControlPlane[name])
+	}
+
+	for _, name := range idx.pvcNames {
+		pvc := ctx.Graph.PVCs[name]
+		ns := idx.namespace(pvc.Namespace)
+		ns.PVCs = append(ns.PVCs, name)
+		idx.addConditions(ObjectKey{KindPVC, pvc.Namespace, name}, pvc.Conditions)
+	}
+
+	for _, name := range idx.datasetNames {
+		dataset := ctx.Graph.Datasets[name]
+		ns := idx.namespace(dataset.Namespace)
+		ns.Datasets = append(ns.Datasets, name)
+		idx.addConditions(ObjectKey{KindDataset, dataset.Namespace, name}, dataset.Conditions)
+	}
+
+	for _, name := range idx.runtimeNames {
+		runtime := ctx.Graph.Runtimes[name]
+		ns := idx.namespace(runtime.Namespace)
+		ns.Runtimes = append(ns.Runtimes, name)
+		idx.addConditions(ObjectKey{KindRuntime, runtime.Namespace, name}, runtime.Conditions)
+	}
+
+	for _, name := range idx.nodeNames {
+		idx.addConditions(ObjectKey{KindNode, "", name}, ctx.Graph.Nodes[name].Conditions)
+	}
+
+	for _, name := range idx.opNames {
+		op := ctx.Graph.DataOperations[name]
+		ns := idx.namespace(op.Namespace)
+		ns.DataOperations = append(ns.DataOperations, name)
+		key := ObjectKey{KindDataset, op.Namespace, op.Dataset}
+		idx.opsByDataset[key] = append(idx.opsByDataset[key], op)
+		idx.addConditions(ObjectKey{op.Kind, op.Namespace, name}, op.Conditions)
+	}
+
+	return idx
+}
+
+func (idx *Index) addPod(name string, pod types.PodInfo) {
+	class := idx.classifier.ClassifyIn(pod, idx.ctx.Graph.Runtimes)
+	idx.podRoles[name] = class
+	idx.podsByRole[class.Role] = append(idx.podsByRole[class.Role], pod)
+	for _, owner := range pod.OwnerReferences {
+		idx.podsByOwner[owner] = append(idx.podsByOwner[owner], pod)
+	}
+	if pod.NodeName != "" {
+		idx.podsByNode[pod.NodeName] = append(idx.podsByNode[pod.NodeName], pod)
+	}
+	for _, claim := range pod.PVCs {
+		key := ObjectKey{KindPVC, pod.Namespace, claim}
+		idx.podsByPVC[key] = append(idx.podsByPVC[key], pod)
+	}
+	ns := idx.namespace(pod.Namespace)
+	ns.Pods = append(ns.Pods, name)
+	idx.addConditions(ObjectKey{KindPod, pod.Namespace, name}, pod.Conditions)
+}
+
+func (idx *Index) namespace(name string) *Namespace {
+	ns, ok := idx.namespaces[name]
+	if !ok {
+		ns = &Namespace{}
+		idx.namespaces[name] = ns
+	}
+	return ns
+}
+
+func (idx *Index) addConditions(obj ObjectKey, conditions []types.Condition) {
+	for _, cond := range conditions {
+		idx.conditions[cond.Type] = append(idx.conditions[cond.Type], ConditionRef{Object: obj, Condition: cond})
+	}
+}
+
+// Context returns the indexed DiagnosticContext.
+func (idx *Index) Context() types.DiagnosticContext {
+	return idx.ctx
+}
+
+// ClusterVersion returns the parsed Summary.ClusterVersion. ok is false when
+// it is missing or unparseable, in which case rules should not assume any
+// particular Kubernetes behaviour.
+func (idx *Index) ClusterVersion() (v version.Version, ok bool) {
+	return idx.clusterVersion, idx.hasClusterVersion
+}
+
+// PodNames returns the keys of Graph.Pods in sorted order.
+func (idx *Index) PodNames() []string {
+	return idx.podNames
+}
+
+// ControlPlane returns the pods of Graph.ControlPlane sorted by name. These
+// pods are also included in the role, owner, node, namespace and condition
+// lookups, where they follow the pods of Graph.Pods.
+func (idx *Index) ControlPlane() []types.PodInfo {
+	return idx.controlPlane
+}
+
+// PVCNames returns the keys of Graph.PVCs in sorted order.
+func (idx *Index) PVCNames() []string {
+	return idx.pvcNames
+}
+
+// PVNames returns the keys of Graph.PVs in sorted order.
+func (idx *Index) PVNames() []string {
+	return idx.pvNames
+}
+
+// StorageClassNames returns the keys of Graph.StorageClasses in sorted order.
+func (idx *Index) StorageClassNames() []string {
+	return idx.classNames
+}
+
+// DatasetNames returns the keys of Graph.Datasets in sorted order.
+func (idx *Index) DatasetNames() []string {
+	return idx.datasetNames
+}
+
+// RuntimeNames returns the keys of Graph.Runtimes in sorted order.
+func (idx *Index) RuntimeNames() []string {
+	return idx.runtimeNames
+}
+
+// NodeNames returns the keys of Graph.Nodes in sorted order.
+func (idx *Index) NodeNames() []string {
+	return idx.nodeNames
+}
+
+// DataOperationNames returns the keys of Graph.DataOperations in sorted order.
+func (idx *Index) DataOperationNames() []string {
+	return idx.opNames
+}
+
+// DataOperationsFor returns the data operations targeting the Dataset
+// namespace/name, sorted by name.
+func (idx *Index) DataOperationsFor(namespace, dataset string) []types.DataOperationInfo {
+	return idx.opsByDataset[ObjectKey{KindDataset, namespace, dataset}]
+}
+
+// JobPods returns the pods owned by op's Job in op's namespace, sorted by
+// name.
+func (idx *Index) JobPods(op types.DataOperationInfo) []types.PodInfo {
+	if op.Job == "" {
+		return nil
+	}
+	var pods []types.PodInfo
+	for _, pod := range idx.PodsOwnedBy(KindJob, op.Job) {
+		if pod.Namespace == op.Namespace {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// EventsFor returns the events whose involved object is kind/namespace/name,
+// in input order.
+func (idx *Index) EventsFor(kind, namespace, name string) []types.Event {
+	return idx.eventsByObject[ObjectKey{kind, namespace, name}]
+}
+
+// EventsWithReason returns the events with the given reason, in input order.
+func (idx *Index) EventsWithReason(reason string) []types.Event {
+	return idx.eventsByReason[reason]
+}
+
+// Role returns the role of the pod stored under name in Graph.Pods or
+// Graph.ControlPlane.
+func (idx *Index) Role(podName string) roles.Role {
+	return idx.podRoles[podName].Role
+}
+
+// Classification returns how the pod stored under name in Graph.Pods or
+// Graph.ControlPlane was classified.
+func (idx *Index) Classification(podName string) roles.Classification {
+	return idx.podRoles[podName]
+}
+
+// EventRole returns the role of the pod an event refers to, or Unknown for
+// events about other kinds. Pods missing from the graph are classified from
+// the event's object reference alone.
+func (idx *Index) EventRole(event types.Event) roles.Role {
+	ref := event.InvolvedObject
+	if ref.Kind != KindPod {
+		return roles.Unknown
+	}
+	if class, ok := idx.podRoles[ref.Name]; ok {
+		return class.Role
+	}
+	return idx.classifier.ClassifyIn(types.PodInfo{Name: ref.Name, Namespace: ref.Namespace}, idx.ctx.Graph.Runtimes).Role
+}
+
+// PodsByRole returns the pods classified as role, sorted by name.
+func (idx *Index) PodsByRole(role roles.Role) []types.PodInfo {
+	return idx.podsByRole[role]
+}
+
+// PodsOwnedBy returns the pods with an owner reference to kind/name, sorted
+// by name.
+func (idx *Index) PodsOwnedBy(kind, name string) []types.PodInfo {
+	return idx.podsByOwner[types.OwnerReference{Kind: kind, Name: name}]
+}
+
+// PodsUsingPVC returns the pods mounting the PVC namespace/name, sorted by
+// name.
+func (idx *Index) PodsUsingPVC(namespace, name string) []types.PodInfo {
+	return idx.podsByPVC[ObjectKey{KindPVC, namespace, name}]
+}
+
+// FuseSidecarPods returns the pods that request Fuse sidecar injection or
+// run an injected Fuse sidecar, sorted by name.
+func (idx *Index) FuseSidecarPods() []types.PodInfo {
+	return idx.sidecarPods
+}
+
+// PodsOnNode returns the pods scheduled onto node, sorted by name.
+func (idx *Index) PodsOnNode(node string) []types.PodInfo {
+	return idx.podsByNode[node]
+}
+
+// Namespace returns the objects in namespace ns.
+func (idx *Index) Namespace(ns string) Namespace {
+	if n, ok := idx.namespaces[ns]; ok {
+		return *n
+	}
+	return Namespace{}
+}
+
+// Namespaces returns the namespaces that contain graph objects, sorted.
+func (idx *Index) Namespaces() []string {
+	return slices.Sorted(maps.Keys(idx.namespaces))
+}
+
+// Conditions returns every condition of the given type reported by a pod
+// (including control-plane pods), PVC, Dataset, Runtime, Node or data
+// operation, in that order of kind and then by name.
+func (idx *Index) Conditions(condType string) []ConditionRef {
+	return idx.conditions[condType]
+}
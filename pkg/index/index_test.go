@@ -0,0 +1,104 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/scenario"
+)
+
+func TestBuild(t *testing.T) {
+	ctx := scenario.New("mydata").
+		Namespace("team-a").
+		Nodes(3).
+		Workers(4).
+		TaintedNodes(1).
+		MemoryPendingWorkers(1).
+		Build()
+	idx := Build(ctx)
+
+	if got := len(idx.PodsByRole(roles.Worker)); got != 4 {
+		t.Errorf("Expected 4 worker pods, got %d", got)
+	}
+	if got := len(idx.PodsByRole(roles.Fuse)); got != 3 {
+		t.Errorf("Expected 3 fuse pods, got %d", got)
+	}
+
+	workers := idx.PodsOwnedBy("StatefulSet", "mydata-worker")
+	if len(workers) != 4 {
+		t.Fatalf("Expected 4 pods owned by the worker StatefulSet, got %d", len(workers))
+	}
+	for i := 1; i < len(workers); i++ {
+		if workers[i-1].Name >= workers[i].Name {
+			t.Errorf("Expected pods sorted by name, got %s before %s", workers[i-1].Name, workers[i].Name)
+		}
+	}
+
+	if len(idx.PodsOnNode("node-0")) != 0 {
+		t.Error("Expected no pods on the tainted node")
+	}
+	if len(idx.PodsOnNode("node-1")) == 0 {
+		t.Error("Expected pods on node-1")
+	}
+
+	pending := idx.PodsByRole(roles.Worker)[0]
+	if events := idx.EventsFor(KindPod, "team-a", pending.Name); len(events) != 1 || events[0].Reason != "FailedScheduling" {
+		t.Errorf("Expected one FailedScheduling event for %s, got %+v", pending.Name, events)
+	}
+	if len(idx.EventsWithReason("FailedScheduling")) != 2 {
+		t.Errorf("Expected 2 FailedScheduling events, got %d", len(idx.EventsWithReason("FailedScheduling")))
+	}
+
+	ns := idx.Namespace("team-a")
+	if len(ns.Pods) != 8 || len(ns.Datasets) != 1 || len(ns.Runtimes) != 1 {
+		t.Errorf("Unexpected namespace contents %+v", ns)
+	}
+	if len(idx.Namespace("other").Pods) != 0 {
+		t.Error("Expected an unknown namespace to be empty")
+	}
+
+	var runtimeReady bool
+	for _, ref := range idx.Conditions("Ready") {
+		if ref.Object.Kind == KindRuntime && ref.Object.Name == "mydata" && ref.Condition.Status == "False" {
+			runtimeReady = true
+		}
+	}
+	if !runtimeReady {
+		t.Error("Expected the runtime Ready=False condition to be indexed")
+	}
+}
+
+func TestBuild_ClusterVersion(t *testing.T) {
+	idx := Build(scenario.New("mydata").ClusterVersion("v1.29.2-eks-5e0fdde").Build())
+	if v, ok := idx.ClusterVersion(); !ok || v.Minor != 29 {
+		t.Errorf("Expected cluster version 1.29, got %v (ok=%v)", v, ok)
+	}
+
+	idx = Build(scenario.New("mydata").ClusterVersion("unknown").Build())
+	if _, ok := idx.ClusterVersion(); ok {
+		t.Error("Expected an unparseable cluster version to be reported as unknown")
+	}
+}
+
+func TestBuild_DataOperations(t *testing.T) {
+	ctx := scenario.New("mydata").OOMKilledDataLoad().Build()
+	idx := Build(ctx)
+
+	names := idx.DataOperationNames()
+	if len(names) != 1 {
+		t.Fatalf("Expected 1 data operation, got %v", names)
+	}
+	op := ctx.Graph.DataOperations[names[0]]
+	if pods := idx.JobPods(op); len(pods) != 1 || pods[0].Status != "Failed" {
+		t.Errorf("Expected one failed job pod, got %+v", pods)
+	}
+	if got := idx.DataOperationsFor("default", "mydata"); len(got) != 1 || got[0].Name != op.Name {
+		t.Errorf("Expected the DataLoad to target mydata, got %+v", got)
+	}
+	if got := idx.Namespace("default").DataOperations; len(got) != 1 {
+		t.Errorf("Expected the data operation in its namespace, got %v", got)
+	}
+	if refs := idx.Conditions("Failed"); len(refs) != 1 || refs[0].Object.Kind != KindDataLoad {
+		t.Errorf("Expected the DataLoad's Failed condition, got %+v", refs)
+	}
+}
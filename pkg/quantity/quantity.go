@@ -0,0 +1,84 @@
+// Package quantity parses the sizes and percentages found in a
+// DiagnosticContext. It understands both Kubernetes quantities ("16Gi",
+// "500M") and the human-readable sizes Fluid writes into Dataset and Runtime
+// status ("2.00GiB", "512.00MiB", "25.0%").
+package quantity
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// multipliers maps a size unit to its value in bytes. Fluid's "B" suffix is
+// optional, so "Gi" and "GiB" are equivalent.
+var multipliers = map[string]float64{
+	"":   1,
+	"k":  1e3,
+	"K":  1e3,
+	"M":  1e6,
+	"G":  1e9,
+	"T":  1e12,
+	"P":  1e15,
+	"E":  1e18,
+	"Ki": 1 << 10,
+	"Mi": 1 << 20,
+	"Gi": 1 << 30,
+	"Ti": 1 << 40,
+	"Pi": 1 << 50,
+	"Ei": 1 << 60,
+}
+
+// ParseBytes parses a size into bytes, rounding up to a whole byte.
+func ParseBytes(s string) (int64, error) {
+	raw := strings.TrimSpace(s)
+	i := strings.IndexFunc(raw, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(raw)
+	}
+	num, unit := raw[:i], strings.TrimSpace(raw[i:])
+	if unit != "B" {
+		unit = strings.TrimSuffix(unit, "B")
+	} else {
+		unit = ""
+	}
+
+	mult, ok := multipliers[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	bytes := math.Ceil(n * mult)
+	if bytes >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q is too large", s)
+	}
+	return int64(bytes), nil
+}
+
+// ParsePercent parses a percentage such as "25.0%" or "25" into 25.0.
+func ParsePercent(s string) (float64, error) {
+	raw := strings.TrimSuffix(strings.TrimSpace(s), "%")
+	p, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil || p < 0 || math.IsInf(p, 0) {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+	return p, nil
+}
+
+// FormatBytes renders n bytes the way Fluid does, e.g. "2.00GiB".
+func FormatBytes(n int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	v := float64(n)
+	i := 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.2f%s", v, units[i])
+}
@@ -0,0 +1,61 @@
+package quantity
+
+import "testing"
+
+func TestParseBytes(t *testing.T) {
+	cases := map[string]int64{
+		"0":         0,
+		"100":       100,
+		"100B":      100,
+		"16Gi":      16 << 30,
+		"2.00GiB":   2 << 30,
+		"512.00MiB": 512 << 20,
+		"1.5Ki":     1536,
+		"500M":      500e6,
+		"1 TiB":     1 << 40,
+		"0.00B":     0,
+	}
+	for in, want := range cases {
+		got, err := ParseBytes(in)
+		if err != nil {
+			t.Errorf("ParseBytes(%q) returned error: %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseBytes(%q) = %d, want %d", in, got, want)
+		}
+	}
+
+	for _, in := range []string{"", "Gi", "-1Gi", "12XB", "1.2.3Gi", "100m", "9999999Ei"} {
+		if _, err := ParseBytes(in); err == nil {
+			t.Errorf("Expected ParseBytes(%q) to fail", in)
+		}
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	for in, want := range map[string]float64{"25.0%": 25, "0%": 0, "100": 100, " 7.5 %": 7.5} {
+		got, err := ParsePercent(in)
+		if err != nil || got != want {
+			t.Errorf("ParsePercent(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "%", "abc%", "-5%"} {
+		if _, err := ParsePercent(in); err == nil {
+			t.Errorf("Expected ParsePercent(%q) to fail", in)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{0: "0.00B", 1023: "1023.00B", 2 << 30: "2.00GiB", 1536 << 20: "1.50GiB"} {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+		if n > 0 {
+			if back, err := ParseBytes(FormatBytes(n)); err != nil || back != n {
+				t.Errorf("ParseBytes(FormatBytes(%d)) = %d, %v", n, back, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Package roles classifies pods into the Fluid components they belong to.
+//
+// Classification is driven by an ordered list of MappingRules; the first rule
+// that matches a pod decides its role. DefaultRules recognises the labels
+// set by the Fluid helm charts and the naming conventions of Fluid
+// workloads, and can be extended or replaced with NewClassifier.
+package roles
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// Role identifies the Fluid component a pod belongs to.
+type Role string
+
+const (
+	Unknown    Role = ""
+	Master     Role = "master"
+	Worker     Role = "worker"
+	Fuse       Role = "fuse"
+	CSIPlugin  Role = "csi-plugin"
+	Controller Role = "controller"
+	Webhook    Role = "webhook"
+)
+
+// MappingRule assigns Role to pods matching every non-empty criterion.
+// Values are path.Match patterns, so "*-worker" matches "alluxio-worker".
+type MappingRule struct {
+	// Name identifies the rule in a Classification.
+	Name string
+	Role Role
+
+	// Labels maps label keys to value patterns. An empty pattern only
+	// requires the label to be present.
+	Labels map[string]string
+
+	// Namespace is a pattern for the pod namespace.
+	Namespace string
+
+	// OwnerKind and OwnerName are patterns matched against a single owner
+	// reference of the pod.
+	OwnerKind string
+	OwnerName string
+
+	// PodName is a pattern for the pod name.
+	PodName string
+
+	// Unowned requires the pod to have no owner references, for rules that
+	// only apply to pods collected without their owners.
+	Unowned bool
+
+	// Runtime requires the pod to belong to a Fluid Runtime: it carries the
+	// fluid.io/dataset-id label, or the matched owner name, or the pod name if
+	// the rule matches no owner, starts with "<runtime>-" for a Runtime in the
+	// pod's namespace. Classify only sees the label, as it has no Runtimes.
+	Runtime bool
+}
+
+// Classification records the role assigned to a pod and the mapping rule
+// that assigned it. Rule is empty for Unknown pods.
+type Classification struct {
+	Role Role   `json:"role"`
+	Rule string `json:"rule,omitempty"`
+}
+
+// Classifier assigns roles to pods using an ordered list of MappingRules.
+type Classifier struct {
+	rules []MappingRule
+}
+
+// NewClassifier returns a Classifier that applies rules in order. It
+// returns an error if any pattern is malformed.
+func NewClassifier(rules ...MappingRule) (*Classifier, error) {
+	for _, r := range rules {
+		patterns := []string{r.Namespace, r.OwnerKind, r.OwnerName, r.PodName}
+		patterns = append(patterns, slices.Collect(maps.Values(r.Labels))...)
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("mapping rule %q: invalid pattern %q: %w", r.Name, p, err)
+			}
+		}
+	}
+	return &Classifier{rules: slices.Clone(rules)}, nil
+}
+
+// Default is the Classifier built from DefaultRules.
+var Default = mustClassifier(DefaultRules()...)
+
+func mustClassifier(rules ...MappingRule) *Classifier {
+	c, err := NewClassifier(rules...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// datasetLabel is set by Fluid on the pods of a Dataset's Runtime.
+const datasetLabel = "fluid.io/dataset-id"
+
+// DefaultRules returns the built-in mapping rules, most specific first.
+func DefaultRules() []MappingRule {
+	return []MappingRule{
+		// Control plane, labelled by the Fluid helm chart
+		{Name: "fluid-webhook-label", Role: Webhook, Labels: map[string]string{"control-plane": "fluid-webhook"}},
+		{Name: "fluid-controller-label", Role: Controller, Labels: map[string]string{"control-plane": "*-controller"}},
+		{Name: "fluid-csi-label", Role: CSIPlugin, Labels: map[string]string{"app": "csi-nodeplugin-fluid"}},
+
+		// Runtime components, labelled role=<runtime>-<component>. Role labels
+		// are common outside Fluid (role: celery-worker), so these and the
+		// owner and name rules below also require the pod to belong to a
+		// Runtime.
+		{Name: "runtime-master-label", Role: Master, Labels: map[string]string{"role": "*-master"}, Runtime: true},
+		{Name: "runtime-worker-label", Role: Worker, Labels: map[string]string{"role": "*-worker"}, Runtime: true},
+		{Name: "runtime-fuse-label", Role: Fuse, Labels: map[string]string{"role": "*-fuse"}, Runtime: true},
+		{Name: "role-master-label", Role: Master, Labels: map[string]string{"role": "master"}, Runtime: true},
+		{Name: "role-worker-label", Role: Worker, Labels: map[string]string{"role": "worker"}, Runtime: true},
+		{Name: "role-fuse-label", Role: Fuse, Labels: map[string]string{"role": "fuse"}, Runtime: true},
+		{Name: "fluid-fuse-label", Role: Fuse, Labels: map[string]string{"fluid.io/fuse": ""}},
+		{Name: "fluid-worker-label", Role: Worker, Labels: map[string]string{"fluid.io/worker": ""}},
+
+		// Owner workloads named after the Dataset
+		{Name: "master-statefulset", Role: Master, OwnerKind: "StatefulSet", OwnerName: "*-master", Runtime: true},
+		{Name: "worker-statefulset", Role: Worker, OwnerKind: "StatefulSet", OwnerName: "*-worker", Runtime: true},
+		{Name: "fuse-daemonset", Role: Fuse, OwnerKind: "DaemonSet", OwnerName: "*-fuse", Runtime: true},
+		{Name: "csi-daemonset", Role: CSIPlugin, OwnerKind: "DaemonSet", OwnerName: "csi-nodeplugin-fluid"},
+
+		// Pod names of the Fluid control plane
+		{Name: "fluid-webhook-name", Role: Webhook, PodName: "fluid-webhook-*"},
+		{Name: "dataset-controller-name", Role: Controller, PodName: "dataset-controller-*"},
+		{Name: "runtime-controller-name", Role: Controller, PodName: "*runtime-controller-*"},
+		{Name: "csi-plugin-name", Role: CSIPlugin, PodName: "csi-nodeplugin-fluid-*"},
+
+		// Runtime pod names, for pods collected without labels or owners
+		{Name: "master-pod-name", Role: Master, PodName: "*-master-[0-9]*", Unowned: true, Runtime: true},
+		{Name: "worker-pod-name", Role: Worker, PodName: "*-worker-[0-9]*", Unowned: true, Runtime: true},
+		{Name: "fuse-pod-name", Role: Fuse, PodName: "*-fuse-?????", Unowned: true, Runtime: true},
+	}
+}
+
+// Of returns the role assigned to pod by the Default classifier.
+func Of(pod types.PodInfo) Role {
+	return Default.Classify(pod).Role
+}
+
+// Classify returns the role of pod according to the first matching rule.
+// Rules that require a Runtime only match pods with the Fluid dataset label;
+// use ClassifyIn when the Runtimes are known.
+func (c *Classifier) Classify(pod types.PodInfo) Classification {
+	return c.ClassifyIn(pod, nil)
+}
+
+// ClassifyIn is like Classify but also applies the rules that require a
+// Runtime, looking them up in runtimes, which is keyed like Graph.Runtimes.
+func (c *Classifier) ClassifyIn(pod types.PodInfo, runtimes map[string]types.RuntimeInfo) Classification {
+	for _, r := range c.rules {
+		if r.matches(pod, runtimes) {
+			return Classification{Role: r.Role, Rule: r.Name}
+		}
+	}
+	return Classification{Role: Unknown}
+}
+
+// ClassifyAll classifies every pod in the context's graph, keyed like
+// Graph.Pods.
+func (c *Classifier) ClassifyAll(ctx types.DiagnosticContext) map[string]Classification {
+	out := make(map[string]Classification, len(ctx.Graph.Pods))
+	for name, pod := range ctx.Graph.Pods {
+		out[name] = c.ClassifyIn(pod, ctx.Graph.Runtimes)
+	}
+	return out
+}
+
+func (r MappingRule) matches(pod types.PodInfo, runtimes map[string]types.RuntimeInfo) bool {
+	if len(r.Labels) == 0 && r.Namespace == "" && r.OwnerKind == "" && r.OwnerName == "" && r.PodName == "" {
+		return false
+	}
+	if r.Unowned && len(pod.OwnerReferences) > 0 {
+		return false
+	}
+
+	for key, pattern := range r.Labels {
+		value, ok := pod.Labels[key]
+		if !ok || (pattern != "" && !match(pattern, value)) {
+			return false
+		}
+	}
+	if r.Namespace != "" && !match(r.Namespace, pod.Namespace) {
+		return false
+	}
+	if r.PodName != "" && !match(r.PodName, pod.Name) {
+		return false
+	}
+
+	if r.OwnerKind != "" || r.OwnerName != "" {
+		for _, owner := range pod.OwnerReferences {
+			if (r.OwnerKind == "" || match(r.OwnerKind, owner.Kind)) &&
+				(r.OwnerName == "" || match(r.OwnerName, owner.Name)) &&
+				(!r.Runtime || inRuntime(pod, owner.Name, runtimes)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return !r.Runtime || inRuntime(pod, pod.Name, runtimes)
+}
+
+// inRuntime reports whether pod belongs to a Fluid Runtime, going by its
+// dataset label or by name, the owner or pod name matched by a rule.
+func inRuntime(pod types.PodInfo, name string, runtimes map[string]types.RuntimeInfo) bool {
+	if _, ok := pod.Labels[datasetLabel]; ok {
+		return true
+	}
+	return namedAfterRuntime(name, pod.Namespace, runtimes)
+}
+
+// namedAfterRuntime reports whether name is "<runtime>-..." for a Runtime in
+// namespace, trying each dash-separated prefix from the longest.
+func namedAfterRuntime(name, namespace string, runtimes map[string]types.RuntimeInfo) bool {
+	for i := strings.LastIndex(name, "-"); i > 0; i = strings.LastIndex(name[:i], "-") {
+		if runtime, ok := runtimes[name[:i]]; ok && runtime.Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func match(pattern, value string) bool {
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
@@ -0,0 +1,192 @@
+package roles
+
+import (
+	"testing"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+func TestDefaultClassifier(t *testing.T) {
+	runtimes := map[string]types.RuntimeInfo{"mydata": {Name: "mydata"}}
+	cases := []struct {
+		name string
+		pod  types.PodInfo
+		want Classification
+	}{
+		{
+			name: "runtime worker label",
+			pod:  types.PodInfo{Name: "mydata-worker-0", Labels: map[string]string{"role": "alluxio-worker"}},
+			want: Classification{Role: Worker, Rule: "runtime-worker-label"},
+		},
+		{
+			name: "runtime master label",
+			pod:  types.PodInfo{Name: "mydata-master-0", Labels: map[string]string{"role": "jindofs-master"}},
+			want: Classification{Role: Master, Rule: "runtime-master-label"},
+		},
+		{
+			name: "legacy fuse label",
+			pod:  types.PodInfo{Name: "x", Labels: map[string]string{"fluid.io/fuse": "true"}},
+			want: Classification{Role: Fuse, Rule: "fluid-fuse-label"},
+		},
+		{
+			name: "fuse daemonset owner",
+			pod: types.PodInfo{Name: "mydata-fuse-x7k2p", OwnerReferences: []types.OwnerReference{
+				{Kind: "DaemonSet", Name: "mydata-fuse"},
+			}},
+			want: Classification{Role: Fuse, Rule: "fuse-daemonset"},
+		},
+		{
+			name: "csi plugin",
+			pod:  types.PodInfo{Name: "csi-nodeplugin-fluid-abcde", Labels: map[string]string{"app": "csi-nodeplugin-fluid"}},
+			want: Classification{Role: CSIPlugin, Rule: "fluid-csi-label"},
+		},
+		{
+			name: "dataset controller",
+			pod:  types.PodInfo{Name: "dataset-controller-5b7c9d-abcde", Labels: map[string]string{"control-plane": "dataset-controller"}},
+			want: Classification{Role: Controller, Rule: "fluid-controller-label"},
+		},
+		{
+			name: "webhook",
+			pod:  types.PodInfo{Name: "fluid-webhook-7f9c-abcde", Labels: map[string]string{"control-plane": "fluid-webhook"}},
+			want: Classification{Role: Webhook, Rule: "fluid-webhook-label"},
+		},
+		{
+			name: "runtime controller by name",
+			pod:  types.PodInfo{Name: "alluxioruntime-controller-6d5f-abcde"},
+			want: Classification{Role: Controller, Rule: "runtime-controller-name"},
+		},
+		{
+			name: "worker statefulset pod name",
+			pod:  types.PodInfo{Name: "mydata-worker-2"},
+			want: Classification{Role: Worker, Rule: "worker-pod-name"},
+		},
+		{
+			name: "app pod with worker in its name",
+			pod: types.PodInfo{Name: "workerpool-api-6d5f-abcde", OwnerReferences: []types.OwnerReference{
+				{Kind: "ReplicaSet", Name: "workerpool-api-6d5f"},
+			}},
+			want: Classification{Role: Unknown},
+		},
+		{
+			name: "app pod with fuse in its name",
+			pod:  types.PodInfo{Name: "fuse-demo", Labels: map[string]string{"app": "fuse-demo"}},
+			want: Classification{Role: Unknown},
+		},
+		{
+			name: "worker statefulset with the dataset label",
+			pod: types.PodInfo{Name: "cache-worker-0", Labels: map[string]string{"fluid.io/dataset-id": "default-cache"}, OwnerReferences: []types.OwnerReference{
+				{Kind: "StatefulSet", Name: "cache-worker"},
+			}},
+			want: Classification{Role: Worker, Rule: "worker-statefulset"},
+		},
+		{
+			name: "worker statefulset named after the runtime",
+			pod: types.PodInfo{Name: "mydata-worker-0", OwnerReferences: []types.OwnerReference{
+				{Kind: "StatefulSet", Name: "mydata-worker"},
+			}},
+			want: Classification{Role: Worker, Rule: "worker-statefulset"},
+		},
+		{
+			name: "deployment pod with a worker-like name",
+			pod: types.PodInfo{Name: "api-worker-5d9f8b7c4-abcde", OwnerReferences: []types.OwnerReference{
+				{Kind: "ReplicaSet", Name: "api-worker-5d9f8b7c4"},
+			}},
+			want: Classification{Role: Unknown},
+		},
+		{
+			name: "celery worker statefulset",
+			pod: types.PodInfo{Name: "celery-worker-0", OwnerReferences: []types.OwnerReference{
+				{Kind: "StatefulSet", Name: "celery-worker"},
+			}},
+			want: Classification{Role: Unknown},
+		},
+		{
+			name: "celery worker without owner",
+			pod:  types.PodInfo{Name: "celery-worker-0"},
+			want: Classification{Role: Unknown},
+		},
+		{
+			name: "postgres master statefulset",
+			pod: types.PodInfo{Name: "postgres-master-0", OwnerReferences: []types.OwnerReference{
+				{Kind: "StatefulSet", Name: "postgres-master"},
+			}},
+			want: Classification{Role: Unknown},
+		},
+		{
+			name: "redis master without owner",
+			pod:  types.PodInfo{Name: "redis-master-0"},
+			want: Classification{Role: Unknown},
+		},
+		{
+			name: "app pod with a worker role label",
+			pod: types.PodInfo{Name: "celery-5d9f8b7c4-abcde", Labels: map[string]string{"role": "celery-worker"}, OwnerReferences: []types.OwnerReference{
+				{Kind: "ReplicaSet", Name: "celery-5d9f8b7c4"},
+			}},
+			want: Classification{Role: Unknown},
+		},
+		{
+			name: "app pod with a bare master role label",
+			pod:  types.PodInfo{Name: "db-0", Labels: map[string]string{"role": "master"}},
+			want: Classification{Role: Unknown},
+		},
+		{
+			name: "runtime pod name in another namespace",
+			pod:  types.PodInfo{Name: "mydata-worker-0", Namespace: "other"},
+			want: Classification{Role: Unknown},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Default.ClassifyIn(tc.pod, runtimes); got != tc.want {
+				t.Errorf("ClassifyIn() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClassify_RuntimeRulesNeedRuntimes(t *testing.T) {
+	pod := types.PodInfo{Name: "mydata-worker-2"}
+	if got := Default.Classify(pod); got.Role != Unknown {
+		t.Errorf("Expected Unknown without Runtimes, got %+v", got)
+	}
+	all := Default.ClassifyAll(types.DiagnosticContext{Graph: types.ResourceGraph{
+		Pods:     map[string]types.PodInfo{pod.Name: pod},
+		Runtimes: map[string]types.RuntimeInfo{"mydata": {Name: "mydata"}},
+	}})
+	if all[pod.Name].Role != Worker {
+		t.Errorf("Expected ClassifyAll to use the context's Runtimes, got %+v", all)
+	}
+}
+
+func TestCustomClassifier(t *testing.T) {
+	c, err := NewClassifier(
+		MappingRule{Name: "team-cache", Role: Worker, Namespace: "team-*", Labels: map[string]string{"cache": "node"}},
+	)
+	if err != nil {
+		t.Fatalf("NewClassifier returned error: %v", err)
+	}
+
+	pod := types.PodInfo{Name: "cache-0", Namespace: "team-a", Labels: map[string]string{"cache": "node"}}
+	if got := c.Classify(pod); got.Role != Worker || got.Rule != "team-cache" {
+		t.Errorf("Expected custom worker classification, got %+v", got)
+	}
+
+	pod.Namespace = "other"
+	if got := c.Classify(pod); got.Role != Unknown {
+		t.Errorf("Expected Unknown outside the namespace pattern, got %+v", got)
+	}
+
+	all := c.ClassifyAll(types.DiagnosticContext{Graph: types.ResourceGraph{
+		Pods: map[string]types.PodInfo{"cache-0": {Name: "cache-0", Namespace: "team-b", Labels: map[string]string{"cache": "node"}}},
+	}})
+	if all["cache-0"].Role != Worker {
+		t.Errorf("Expected ClassifyAll to classify cache-0 as worker, got %+v", all)
+	}
+}
+
+func TestNewClassifier_InvalidPattern(t *testing.T) {
+	if _, err := NewClassifier(MappingRule{Name: "bad", Role: Worker, PodName: "[worker"}); err == nil {
+		t.Error("Expected an error for a malformed pattern")
+	}
+}
@@ -0,0 +1,259 @@
+package rules
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/scheduling"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// affinityMismatch reports whether a scheduler message blames node affinity
+// or nodeSelector for some of the nodes.
+func affinityMismatch(message string) bool {
+	m, _ := scheduling.Parse(message)
+	return m.Count(scheduling.NodeAffinity) > 0
+}
+
+// labelRequirement is a node requirement of a Runtime's workers together
+// with where it was declared.
+type labelRequirement struct {
+	source string // e.g. "Runtime default/mydata worker nodeSelector"
+	req    types.NodeSelectorRequirement
+}
+
+func (l labelRequirement) String() string {
+	if l.req.Operator == "In" && len(l.req.Values) == 1 {
+		return fmt.Sprintf("%s %s=%s", l.source, l.req.Key, l.req.Values[0])
+	}
+	return fmt.Sprintf("%s %s %s %v", l.source, l.req.Key, l.req.Operator, l.req.Values)
+}
+
+// NodeSelectorMismatchRule detects workers left Pending because their
+// nodeSelector or the Dataset's nodeAffinity matches no node, and names the
+// label keys no node satisfies.
+type NodeSelectorMismatchRule struct{}
+
+func (r *NodeSelectorMismatchRule) ID() string {
+	return "node-selector-mismatch"
+}
+
+func (r *NodeSelectorMismatchRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var hypotheses []types.Hypothesis
+	graph := idx.Context().Graph
+
+	var nodes []types.NodeInfo
+	for _, name := range idx.NodeNames() {
+		if node := graph.Nodes[name]; node.Labels != nil {
+			nodes = append(nodes, node)
+		}
+	}
+
+	for _, name := range idx.RuntimeNames() {
+		runtime := graph.Runtimes[name]
+
+		var evidence []string
+		for _, pod := range runtimePods(idx, runtime, roles.Worker) {
+			if pod.Status == "Pending" {
+				evidence = append(evidence, affinityEvidence(idx, pod)...)
+			}
+		}
+		if len(evidence) == 0 {
+			continue
+		}
+
+		h := types.Hypothesis{
+			Confidence: types.ConfidencePodStatusOnly,
+			Severity:   types.SeverityHigh,
+			Component:  "Worker",
+			Issue:      "Worker pods cannot be scheduled: no node matches their node affinity/selector",
+			Suggestion: "Compare the Runtime's spec.worker.nodeSelector and the Dataset's spec.nodeAffinity with the node labels (kubectl get nodes --show-labels), then fix the typo or label the intended nodes.",
+			Objects:    []types.ObjectReference{datasetRef(runtime.Namespace, name)},
+		}
+
+		// Without node labels the scheduler message is all there is
+		if len(nodes) > 0 {
+			selector, terms := workerRequirements(graph, runtime)
+			var keys []string
+			for _, l := range unsatisfiable(selector, terms, nodes) {
+				evidence = append(evidence, fmt.Sprintf("%s: %s", l, describeMismatch(l.req, nodes)))
+				if !slices.Contains(keys, l.req.Key) {
+					keys = append(keys, l.req.Key)
+				}
+			}
+
+			switch {
+			case len(keys) > 0:
+				h.Confidence = types.ConfidenceEventAndStatus
+				h.Issue = fmt.Sprintf("Worker pods cannot be scheduled: no node matches label %s", strings.Join(keys, ", "))
+				h.Suggestion = "Fix the cited label in the Runtime's spec.worker.nodeSelector or the Dataset's spec.nodeAffinity, or label the intended nodes with kubectl label node."
+			case len(selector)+len(terms) > 0 && !anyNodeMatches(selector, terms, nodes):
+				evidence = append(evidence, "No single node satisfies all worker node requirements together")
+				h.Confidence = types.ConfidenceEventAndStatus
+			}
+		}
+
+		h.Evidence = evidence
+		hypotheses = append(hypotheses, h)
+	}
+
+	return hypotheses
+}
+
+// affinityEvidence returns the scheduler's affinity complaint about pod,
+// from its PodScheduled condition or else from its FailedScheduling events.
+func affinityEvidence(idx *index.Index, pod types.PodInfo) []string {
+	for _, cond := range pod.Conditions {
+		if cond.Type == "PodScheduled" && cond.Status == "False" && affinityMismatch(cond.Message) {
+			return []string{fmt.Sprintf("Pod %s/%s: %s", pod.Namespace, pod.Name, schedulingBreakdown(cond.Message))}
+		}
+	}
+	for _, event := range idx.EventsFor(index.KindPod, pod.Namespace, pod.Name) {
+		if event.Reason == "FailedScheduling" && affinityMismatch(event.Message) {
+			return []string{fmt.Sprintf("Event on pod %s: %s - %s", pod.Name, event.Reason, schedulingBreakdown(event.Message))}
+		}
+	}
+	return nil
+}
+
+// workerRequirements returns the Runtime's worker nodeSelector, as In
+// requirements in key order, and the node affinity terms of its Dataset.
+func workerRequirements(graph types.ResourceGraph, runtime types.RuntimeInfo) ([]labelRequirement, [][]labelRequirement) {
+	var selector []labelRequirement
+	for _, key := range slices.Sorted(maps.Keys(runtime.WorkerNodeSelector)) {
+		selector = append(selector, labelRequirement{
+			source: fmt.Sprintf("Runtime %s/%s worker nodeSelector", runtime.Namespace, runtime.Name),
+			req:    types.NodeSelectorRequirement{Key: key, Operator: "In", Values: []string{runtime.WorkerNodeSelector[key]}},
+		})
+	}
+
+	var terms [][]labelRequirement
+	dataset, ok := graph.Datasets[runtime.Name]
+	if !ok || dataset.Namespace != runtime.Namespace {
+		return selector, nil
+	}
+	for i, term := range dataset.NodeAffinity {
+		var reqs []labelRequirement
+		for _, req := range term.MatchExpressions {
+			reqs = append(reqs, labelRequirement{
+				source: fmt.Sprintf("Dataset %s/%s nodeAffinity term %d", dataset.Namespace, dataset.Name, i),
+				req:    req,
+			})
+		}
+		terms = append(terms, reqs)
+	}
+	return selector, terms
+}
+
+// unsatisfiable returns the requirements that no node satisfies on its own.
+// Affinity requirements are only returned when every term has one, since a
+// single satisfiable term is enough for the scheduler.
+func unsatisfiable(selector []labelRequirement, terms [][]labelRequirement, nodes []types.NodeInfo) []labelRequirement {
+	var out []labelRequirement
+	for _, l := range selector {
+		if !anyNode(nodes, l.req) {
+			out = append(out, l)
+		}
+	}
+
+	var affinity []labelRequirement
+	for _, term := range terms {
+		before := len(affinity)
+		for _, l := range term {
+			if !anyNode(nodes, l.req) {
+				affinity = append(affinity, l)
+			}
+		}
+		if len(affinity) == before {
+			return out
+		}
+	}
+	return append(out, affinity...)
+}
+
+// anyNodeMatches reports whether some node satisfies the whole selector and
+// at least one affinity term, if there are any.
+func anyNodeMatches(selector []labelRequirement, terms [][]labelRequirement, nodes []types.NodeInfo) bool {
+	satisfies := func(node types.NodeInfo, reqs []labelRequirement) bool {
+		for _, l := range reqs {
+			if !matchesRequirement(node.Labels, l.req) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, node := range nodes {
+		if !satisfies(node, selector) {
+			continue
+		}
+		if len(terms) == 0 {
+			return true
+		}
+		for _, term := range terms {
+			if satisfies(node, term) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func anyNode(nodes []types.NodeInfo, req types.NodeSelectorRequirement) bool {
+	for _, node := range nodes {
+		if matchesRequirement(node.Labels, req) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRequirement evaluates req against node labels with Kubernetes
+// semantics. Unknown operators never match.
+func matchesRequirement(labels map[string]string, req types.NodeSelectorRequirement) bool {
+	value, ok := labels[req.Key]
+	switch req.Operator {
+	case "In":
+		return ok && slices.Contains(req.Values, value)
+	case "NotIn":
+		return !ok || !slices.Contains(req.Values, value)
+	case "Exists":
+		return ok
+	case "DoesNotExist":
+		return !ok
+	case "Gt", "Lt":
+		if !ok || len(req.Values) != 1 {
+			return false
+		}
+		have, err1 := strconv.ParseInt(value, 10, 64)
+		want, err2 := strconv.ParseInt(req.Values[0], 10, 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if req.Operator == "Gt" {
+			return have > want
+		}
+		return have < want
+	}
+	return false
+}
+
+// describeMismatch explains why no node satisfies req, citing the values the
+// nodes carry for its key.
+func describeMismatch(req types.NodeSelectorRequirement, nodes []types.NodeInfo) string {
+	var values []string
+	for _, node := range nodes {
+		if v, ok := node.Labels[req.Key]; ok && !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return fmt.Sprintf("no node has label %s", req.Key)
+	}
+	slices.Sort(values)
+	return fmt.Sprintf("no node matches; nodes have %s=%s", req.Key, strings.Join(values, ","))
+}
@@ -0,0 +1,217 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/quantity"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+const (
+	// cacheFullRatio is the share of cache capacity above which a cache is full.
+	cacheFullRatio = 0.95
+
+	// lowHitRatio is the cache hit ratio, in percent, below which the cache is
+	// no longer serving reads.
+	lowHitRatio = 50.0
+)
+
+// cacheStats is the parsed cache status of a Dataset. A size is -1 when the
+// Dataset does not report it or reports it in an unknown format.
+type cacheStats struct {
+	capacity int64
+	cached   int64
+	ufsTotal int64
+	hitRatio float64 // -1 when unknown
+}
+
+func datasetCache(dataset types.DatasetInfo) cacheStats {
+	stats := cacheStats{
+		capacity: parseSize(dataset.CacheCapacity),
+		cached:   parseSize(dataset.Cached),
+		ufsTotal: parseSize(dataset.UfsTotal),
+		hitRatio: -1,
+	}
+	if p, err := quantity.ParsePercent(dataset.CacheHitRatio); err == nil {
+		stats.hitRatio = p
+	}
+	// Older runtimes only report the cached percentage of the UFS
+	if p, err := quantity.ParsePercent(dataset.CachedPercentage); err == nil && stats.cached < 0 && stats.ufsTotal >= 0 {
+		stats.cached = int64(float64(stats.ufsTotal) * p / 100)
+	}
+	return stats
+}
+
+func parseSize(s string) int64 {
+	if s == "" {
+		return -1
+	}
+	n, err := quantity.ParseBytes(s)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// tieredStoreCapacity returns the total cache capacity of runtime's tiered
+// store across all worker replicas, or -1 if it cannot be determined.
+func tieredStoreCapacity(runtime types.RuntimeInfo) int64 {
+	if len(runtime.TieredStore) == 0 || runtime.WorkerReplicas <= 0 {
+		return -1
+	}
+	var perWorker int64
+	for _, level := range runtime.TieredStore {
+		quota := parseSize(level.Quota)
+		if quota < 0 {
+			return -1
+		}
+		perWorker += quota
+	}
+	return perWorker * int64(runtime.WorkerReplicas)
+}
+
+// CacheFullRule detects Dataset caches that are full while part of the
+// Dataset is still uncached, so every new read evicts cached data.
+type CacheFullRule struct{}
+
+func (r *CacheFullRule) ID() string {
+	return "cache-full"
+}
+
+func (r *CacheFullRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var hypotheses []types.Hypothesis
+
+	for _, name := range idx.DatasetNames() {
+		dataset := idx.Context().Graph.Datasets[name]
+		stats := datasetCache(dataset)
+		if stats.capacity <= 0 || stats.cached < 0 || float64(stats.cached) < cacheFullRatio*float64(stats.capacity) {
+			continue
+		}
+		// A cache holding the whole Dataset is complete, not full
+		if stats.ufsTotal >= 0 && stats.cached >= stats.ufsTotal {
+			continue
+		}
+
+		evidence := []string{fmt.Sprintf("Dataset %s/%s: cached %s of %s cache capacity",
+			dataset.Namespace, name, quantity.FormatBytes(stats.cached), quantity.FormatBytes(stats.capacity))}
+		if stats.ufsTotal > 0 {
+			evidence = append(evidence, fmt.Sprintf("Dataset %s/%s: UFS total %s, %.1f%% cached",
+				dataset.Namespace, name, quantity.FormatBytes(stats.ufsTotal), 100*float64(stats.cached)/float64(stats.ufsTotal)))
+		}
+
+		h := types.Hypothesis{
+			Confidence: types.ConfidenceConditionOnly,
+			Severity:   types.SeverityMedium,
+			Component:  "Cache",
+			Issue:      "Dataset cache is full, so reads of uncached data evict cached data",
+			Objects:    []types.ObjectReference{datasetRef(dataset.Namespace, name)},
+			Suggestion: "Increase the tiered-store quota or the number of workers, or narrow the data being read. Use a DataLoad to warm only the hot paths.",
+		}
+		if stats.hitRatio >= 0 && stats.hitRatio < lowHitRatio {
+			evidence = append(evidence, fmt.Sprintf("Dataset %s/%s: cache hit ratio %.1f%%", dataset.Namespace, name, stats.hitRatio))
+			h.Confidence = types.ConfidencePodStatusOnly
+			h.Issue = "Dataset cache is full and its hit ratio has collapsed"
+		}
+		h.Evidence = evidence
+		hypotheses = append(hypotheses, h)
+	}
+
+	return hypotheses
+}
+
+// CacheNoProgressRule detects bound Datasets that cache nothing, either
+// because they have no cache capacity or although a DataLoad completed.
+type CacheNoProgressRule struct{}
+
+func (r *CacheNoProgressRule) ID() string {
+	return "cache-no-progress"
+}
+
+func (r *CacheNoProgressRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var hypotheses []types.Hypothesis
+
+	for _, name := range idx.DatasetNames() {
+		dataset := idx.Context().Graph.Datasets[name]
+		stats := datasetCache(dataset)
+		if dataset.Status != "Bound" || stats.ufsTotal <= 0 || stats.cached != 0 {
+			continue
+		}
+
+		var evidence []string
+		confidence := types.ConfidenceConditionOnly
+		if stats.capacity == 0 {
+			evidence = append(evidence, fmt.Sprintf("Dataset %s/%s: cache capacity 0, UFS total %s",
+				dataset.Namespace, name, quantity.FormatBytes(stats.ufsTotal)))
+		}
+		for _, op := range idx.DataOperationsFor(dataset.Namespace, name) {
+			if op.Kind == index.KindDataLoad && op.Phase == "Complete" {
+				evidence = append(evidence, fmt.Sprintf("DataLoad %s/%s: Phase=Complete, but Dataset %s/%s has 0 bytes cached",
+					op.Namespace, op.Name, dataset.Namespace, name))
+				confidence = types.ConfidencePodStatusOnly
+			}
+		}
+		if len(evidence) == 0 {
+			continue
+		}
+
+		h := types.Hypothesis{
+			Confidence: confidence,
+			Severity:   types.SeverityMedium,
+			Component:  "Cache",
+			Issue:      "Dataset caches nothing although data was loaded",
+			Evidence:   evidence,
+			Objects:    []types.ObjectReference{datasetRef(dataset.Namespace, name)},
+			Suggestion: "Check the worker logs for cache write errors and verify the tiered-store path is writable. A DataLoad that completes without caching usually means the workers cannot store blocks.",
+		}
+		if stats.capacity == 0 {
+			h.Issue = "Dataset has no cache capacity, so nothing can be cached"
+			h.Suggestion = "Check the Runtime's tiered-store levels and worker replicas. Every worker needs a non-zero quota for the Dataset to be cached."
+		}
+		hypotheses = append(hypotheses, h)
+	}
+
+	return hypotheses
+}
+
+// UFSExceedsCacheRule detects Datasets larger than their total cache
+// capacity, which can never be fully cached. Datasets that do not report a
+// capacity are compared with their Runtime's tiered store instead.
+type UFSExceedsCacheRule struct{}
+
+func (r *UFSExceedsCacheRule) ID() string {
+	return "ufs-exceeds-cache"
+}
+
+func (r *UFSExceedsCacheRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var evidence []string
+	var objects []types.ObjectReference
+
+	for _, name := range idx.DatasetNames() {
+		dataset := idx.Context().Graph.Datasets[name]
+		stats := datasetCache(dataset)
+		capacity, source := stats.capacity, "cache capacity"
+		if runtime, ok := idx.Context().Graph.Runtimes[name]; ok && capacity < 0 && runtime.Namespace == dataset.Namespace {
+			capacity, source = tieredStoreCapacity(runtime), "tiered-store capacity"
+		}
+		if capacity > 0 && stats.ufsTotal > capacity {
+			evidence = append(evidence, fmt.Sprintf("Dataset %s/%s: UFS total %s exceeds %s %s",
+				dataset.Namespace, name, quantity.FormatBytes(stats.ufsTotal), source, quantity.FormatBytes(capacity)))
+			objects = append(objects, datasetRef(dataset.Namespace, name))
+		}
+	}
+
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	return []types.Hypothesis{{
+		Confidence: types.ConfidenceConditionOnly,
+		Severity:   types.SeverityLow,
+		Component:  "Cache",
+		Issue:      "Dataset is larger than its cache capacity, so it can never be fully cached",
+		Evidence:   evidence,
+		Suggestion: "Size the tiered store (quota times workers) to the working set. If only part of the Dataset is hot, this is expected; otherwise add capacity.",
+		Objects:    objects,
+	}}
+}
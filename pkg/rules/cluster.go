@@ -0,0 +1,75 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/version"
+)
+
+// fluidSupport lists the Kubernetes versions each Fluid release line
+// supports, with the basis of each range. Keep it in sync with the Fluid
+// release notes; a Fluid version matching no entry is not checked. Upper
+// bounds are only set where a Kubernetes API removal breaks the release line.
+var fluidSupport = []struct {
+	fluid      version.Constraint
+	kubernetes version.Constraint
+	source     string
+}{
+	{
+		version.MustParseConstraint("<0.8.0"), version.MustParseConstraint(">=1.14 <1.22"),
+		"Fluid releases before 0.8 install v1beta1 CRDs and webhook configurations, which Kubernetes 1.22 removed (https://kubernetes.io/docs/reference/using-api/deprecation-guide/#v1-22)",
+	},
+	{
+		version.MustParseConstraint(">=0.8.0 <1.0.0"), version.MustParseConstraint(">=1.18"),
+		"Fluid 0.8 and later require Kubernetes 1.18 or newer, per the Fluid installation guide",
+	},
+	{
+		version.MustParseConstraint(">=1.0.0"), version.MustParseConstraint(">=1.18"),
+		"Fluid 1.0 and later require Kubernetes 1.18 or newer, per the Fluid installation guide",
+	},
+}
+
+// fluidVersion returns the installed Fluid release, if the context reports it.
+func fluidVersion(idx *index.Index) (version.Version, bool) {
+	v, err := version.Parse(idx.Context().Summary.FluidVersion)
+	return v, err == nil
+}
+
+// UnsupportedClusterVersionRule warns when the cluster's Kubernetes version
+// is outside the range supported by the installed Fluid release.
+type UnsupportedClusterVersionRule struct{}
+
+func (r *UnsupportedClusterVersionRule) ID() string {
+	return "unsupported-cluster-version"
+}
+
+func (r *UnsupportedClusterVersionRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	cluster, ok := idx.ClusterVersion()
+	if !ok {
+		return nil
+	}
+	fluid, ok := fluidVersion(idx)
+	if !ok {
+		return nil
+	}
+
+	for _, support := range fluidSupport {
+		if !support.fluid.Check(fluid) || support.kubernetes.Check(cluster) {
+			continue
+		}
+		return []types.Hypothesis{{
+			Confidence: types.ConfidenceLow,
+			Severity:   types.SeverityMedium,
+			Component:  "Cluster",
+			Issue:      "Kubernetes version is not supported by the installed Fluid release",
+			Evidence: []string{
+				fmt.Sprintf("Cluster version %s, Fluid %s supports Kubernetes %s", cluster, fluid, support.kubernetes),
+				"Support range: " + support.source,
+			},
+			Suggestion: "Upgrade Fluid to a release that supports this Kubernetes version, or check the Fluid release notes for known incompatibilities before debugging further.",
+		}}
+	}
+	return nil
+}
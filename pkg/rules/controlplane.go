@@ -0,0 +1,224 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// controlPlaneEvidence returns evidence for every unhealthy control-plane pod
+// with the given role, and whether any of it came from a Warning event.
+func controlPlaneEvidence(idx *index.Index, role roles.Role) (evidence []string, events bool) {
+	for _, pod := range idx.ControlPlane() {
+		if idx.Role(pod.Name) != role {
+			continue
+		}
+		problems := podProblems(pod)
+		for _, problem := range problems {
+			evidence = append(evidence, fmt.Sprintf("Pod %s/%s: %s", pod.Namespace, pod.Name, problem))
+		}
+		if len(problems) == 0 {
+			continue
+		}
+		for _, event := range idx.EventsFor(index.KindPod, pod.Namespace, pod.Name) {
+			if event.Type == "Warning" {
+				evidence = append(evidence, fmt.Sprintf("Event on pod %s: %s - %s", pod.Name, event.Reason, event.Message))
+				events = true
+			}
+		}
+	}
+	return evidence, events
+}
+
+// hasControlPlaneRole reports whether any control-plane pod has role.
+func hasControlPlaneRole(idx *index.Index, role roles.Role) bool {
+	for _, pod := range idx.ControlPlane() {
+		if idx.Role(pod.Name) == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ControllerUnhealthyRule detects Fluid dataset or runtime controllers that are
+// crash-looping, not running, or absent from a collected control plane.
+type ControllerUnhealthyRule struct{}
+
+func (r *ControllerUnhealthyRule) ID() string {
+	return "controller-unhealthy"
+}
+
+func (r *ControllerUnhealthyRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	// Without control-plane data there is nothing to judge
+	if len(idx.ControlPlane()) == 0 {
+		return nil
+	}
+
+	evidence, events := controlPlaneEvidence(idx, roles.Controller)
+	confidence := types.ConfidencePodStatusOnly
+	if events {
+		confidence = types.ConfidenceEventAndStatus
+	}
+	if !hasControlPlaneRole(idx, roles.Controller) {
+		evidence = append(evidence, fmt.Sprintf("Control plane: no controller pods among %d collected pods",
+			len(idx.ControlPlane())))
+		confidence = types.ConfidenceConditionOnly
+	}
+
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	return []types.Hypothesis{{
+		Confidence: confidence,
+		Severity:   types.SeverityCritical,
+		Component:  "Controller",
+		Issue:      "Fluid controller is unhealthy, so Dataset and Runtime status is not being reconciled",
+		Evidence:   evidence,
+		Suggestion: "Check the dataset and runtime controller pods in the Fluid namespace (logs, restarts, resource limits, RBAC). Dataset and Runtime status will not change until the controllers are running.",
+	}}
+}
+
+// StaleStatusRule detects Runtime and Dataset status that contradicts the
+// observed pods, and attributes it to the controller when the controller is
+// unhealthy.
+type StaleStatusRule struct{}
+
+func (r *StaleStatusRule) ID() string {
+	return "stale-status"
+}
+
+func (r *StaleStatusRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var evidence []string
+	var objects []types.ObjectReference
+	graph := idx.Context().Graph
+
+	for _, name := range idx.RuntimeNames() {
+		runtime := graph.Runtimes[name]
+		for _, c := range []struct {
+			role     roles.Role
+			label    string
+			reported int32
+		}{
+			{roles.Master, "Master", runtime.MasterReady},
+			{roles.Worker, "Worker", runtime.WorkerReady},
+			{roles.Fuse, "Fuse", runtime.FuseReady},
+		} {
+			pods := runtimePods(idx, runtime, c.role)
+			if len(pods) == 0 {
+				continue
+			}
+			ready := int32(0)
+			for _, pod := range pods {
+				if len(podProblems(pod)) == 0 {
+					ready++
+				}
+			}
+			if ready != c.reported {
+				evidence = append(evidence, fmt.Sprintf("Runtime %s/%s: status reports %d %s pods ready, but %d of %d are ready",
+					runtime.Namespace, name, c.reported, strings.ToLower(c.label), ready, len(pods)))
+				objects = appendObject(objects, datasetRef(runtime.Namespace, name))
+			}
+		}
+	}
+
+	for _, name := range idx.DatasetNames() {
+		dataset := graph.Datasets[name]
+		runtime, ok := graph.Runtimes[name]
+		if ok && runtime.Namespace == dataset.Namespace && dataset.Status != "Bound" &&
+			runtime.Phase == "Ready" {
+			evidence = append(evidence, fmt.Sprintf("Dataset %s/%s: Status=%s, but Runtime phase is Ready",
+				dataset.Namespace, name, dataset.Status))
+			objects = appendObject(objects, datasetRef(dataset.Namespace, name))
+		}
+	}
+
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	controllerEvidence, _ := controlPlaneEvidence(idx, roles.Controller)
+	if len(controllerEvidence) == 0 {
+		return []types.Hypothesis{{
+			Confidence: types.ConfidenceLow,
+			Severity:   types.SeverityMedium,
+			Component:  "Controller",
+			Issue:      "Runtime or Dataset status disagrees with pod state and may be stale",
+			Evidence:   evidence,
+			Suggestion: "Re-collect diagnostics to rule out a timing difference. If the mismatch persists, check the Fluid controller logs for reconcile errors.",
+			Objects:    objects,
+		}}
+	}
+
+	return []types.Hypothesis{{
+		Confidence: types.ConfidenceEventAndStatus,
+		Severity:   types.SeverityHigh,
+		Component:  "Controller",
+		Issue:      "Runtime or Dataset status is stale because the Fluid controller is unhealthy",
+		Evidence:   append(evidence, controllerEvidence...),
+		Suggestion: "Do not trust Runtime or Dataset status until the controller is healthy. Restore the controller first, then re-check status.",
+		Objects:    objects,
+	}}
+}
+
+// failedWebhook returns the webhook named in an API server "failed calling
+// webhook" error, e.g. `failed calling webhook "fluid-pod-admission-webhook.fluid.io": ...`.
+func failedWebhook(message string) (string, bool) {
+	_, rest, ok := strings.Cut(message, `failed calling webhook "`)
+	if !ok {
+		return "", false
+	}
+	name, _, ok := strings.Cut(rest, `"`)
+	return name, ok && name != ""
+}
+
+// WebhookUnavailableRule detects "failed calling webhook" errors for Fluid's
+// webhook, which block the creation of pods that it must mutate. Failures of
+// other admission webhooks, such as a service mesh injector, are not Fluid's.
+type WebhookUnavailableRule struct{}
+
+func (r *WebhookUnavailableRule) ID() string {
+	return "webhook-unavailable"
+}
+
+func (r *WebhookUnavailableRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var evidence []string
+
+	for _, event := range idx.Context().Events {
+		if event.Type != "Warning" {
+			continue
+		}
+		name, ok := failedWebhook(event.Message)
+		if !ok || !strings.Contains(strings.ToLower(name), "fluid") {
+			continue
+		}
+		evidence = append(evidence, fmt.Sprintf("Event on %s %s: webhook %s: %s - %s",
+			event.InvolvedObject.Kind, event.InvolvedObject.Name, name, event.Reason, event.Message))
+	}
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	confidence := types.ConfidenceEventOnly
+	webhookEvidence, _ := controlPlaneEvidence(idx, roles.Webhook)
+	if len(webhookEvidence) > 0 {
+		confidence = types.ConfidenceEventAndStatus
+		evidence = append(evidence, webhookEvidence...)
+	} else if len(idx.ControlPlane()) > 0 && !hasControlPlaneRole(idx, roles.Webhook) {
+		confidence = types.ConfidenceEventAndStatus
+		evidence = append(evidence, fmt.Sprintf("Control plane: no webhook pods among %d collected pods",
+			len(idx.ControlPlane())))
+	}
+
+	return []types.Hypothesis{{
+		Confidence: confidence,
+		Severity:   types.SeverityHigh,
+		Component:  "Webhook",
+		Issue:      "Fluid webhook is unreachable, so pods using Fluid volumes cannot be created",
+		Evidence:   evidence,
+		Suggestion: "Check the fluid-webhook pods and service endpoints, and verify the webhook certificate. Ensure the API server can reach the webhook service.",
+	}}
+}
@@ -0,0 +1,211 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// jobErrorKeywords identify job pod log lines that explain a failed data operation.
+var jobErrorKeywords = []string{"error", "exception", "killed"}
+
+// jobPodProblems is like podProblems for job pods, which are healthy once
+// they have Succeeded.
+func jobPodProblems(pod types.PodInfo) []string {
+	if pod.Status == "Succeeded" {
+		return nil
+	}
+	return podProblems(pod)
+}
+
+// oomKilled reports whether any container of pod was OOMKilled.
+func oomKilled(pod types.PodInfo) bool {
+	for _, cs := range pod.ContainerStatuses {
+		if cs.Reason == "OOMKilled" || cs.LastTerminationReason == "OOMKilled" {
+			return true
+		}
+	}
+	return false
+}
+
+// DataOperationFailedRule detects DataLoad, DataMigrate, DataBackup and
+// DataProcess operations that failed, or that are executing or failed with
+// job pods that were OOMKilled. Completed operations are never reported.
+type DataOperationFailedRule struct{}
+
+func (r *DataOperationFailedRule) ID() string {
+	return "data-operation-failed"
+}
+
+func (r *DataOperationFailedRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var hypotheses []types.Hypothesis
+
+	for _, name := range idx.DataOperationNames() {
+		op := idx.Context().Graph.DataOperations[name]
+		if op.Phase == "Complete" {
+			continue
+		}
+		pods := idx.JobPods(op)
+
+		// Past OOMKills only matter while the operation can still fail on them
+		oom := false
+		if op.Phase == "Executing" || op.Phase == "Failed" {
+			for _, pod := range pods {
+				oom = oom || oomKilled(pod)
+			}
+		}
+		if op.Phase != "Failed" && !oom {
+			continue
+		}
+
+		var evidence []string
+		confidence := types.ConfidenceConditionOnly
+		if op.Phase == "Failed" {
+			evidence = append(evidence, fmt.Sprintf("%s %s/%s: Phase=Failed", op.Kind, op.Namespace, name))
+		}
+		for _, cond := range op.Conditions {
+			if cond.Type == "Failed" && cond.Status == "True" {
+				evidence = append(evidence, fmt.Sprintf("%s %s/%s: Condition Failed=True, reason=%s, message=%s",
+					op.Kind, op.Namespace, name, cond.Reason, cond.Message))
+			}
+		}
+		for _, event := range idx.EventsFor(op.Kind, op.Namespace, name) {
+			if event.Type == "Warning" {
+				evidence = append(evidence, fmt.Sprintf("Event on %s %s: %s - %s", op.Kind, name, event.Reason, event.Message))
+				confidence = types.ConfidenceEventAndStatus
+			}
+		}
+
+		// Gather evidence from the job pods and their logs
+		for _, pod := range pods {
+			for _, problem := range jobPodProblems(pod) {
+				evidence = append(evidence, fmt.Sprintf("Pod %s/%s: %s", pod.Namespace, pod.Name, problem))
+				confidence = types.ConfidenceEventAndStatus
+			}
+			// Containers that recovered from an OOMKill are not problems any more
+			for _, cs := range pod.ContainerStatuses {
+				if (cs.Ready || pod.Status == "Succeeded") && (cs.Reason == "OOMKilled" || cs.LastTerminationReason == "OOMKilled") {
+					evidence = append(evidence, fmt.Sprintf("Pod %s/%s: container %s OOMKilled, restarts=%d",
+						pod.Namespace, pod.Name, cs.Name, cs.RestartCount))
+				}
+			}
+			if log, ok := idx.Context().Logs[pod.Name]; ok {
+				for _, line := range matchLogLines(log, jobErrorKeywords, 3) {
+					evidence = append(evidence, fmt.Sprintf("Log %s: %s", pod.Name, line))
+				}
+			}
+		}
+
+		h := types.Hypothesis{
+			Confidence: confidence,
+			Severity:   types.SeverityMedium,
+			Component:  "DataOperation",
+			Issue:      fmt.Sprintf("%s failed", op.Kind),
+			Evidence:   evidence,
+			Objects:    []types.ObjectReference{datasetRef(op.Namespace, op.Dataset)},
+			Suggestion: fmt.Sprintf("Check the %s job pod logs and the conditions of %s %s. Fix the cause, then delete and recreate the %s to retry.",
+				op.Kind, op.Kind, name, op.Kind),
+		}
+		if oom {
+			h.Issue = fmt.Sprintf("%s job was OOMKilled", op.Kind)
+			h.Suggestion = fmt.Sprintf("Raise the memory limit of the %s job, or split the operation into smaller paths. The job pod needs memory proportional to the files it handles at once.",
+				op.Kind)
+		}
+		hypotheses = append(hypotheses, h)
+	}
+
+	return hypotheses
+}
+
+// DataOperationStuckRule detects data operations that cannot make progress,
+// because their target Dataset is not bound or their job pods cannot be
+// scheduled.
+type DataOperationStuckRule struct{}
+
+func (r *DataOperationStuckRule) ID() string {
+	return "data-operation-stuck"
+}
+
+func (r *DataOperationStuckRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var hypotheses []types.Hypothesis
+	graph := idx.Context().Graph
+
+	for _, name := range idx.DataOperationNames() {
+		op := graph.DataOperations[name]
+		if op.Phase == "Complete" || op.Phase == "Failed" {
+			continue
+		}
+
+		// A data operation can only run against a bound Dataset
+		dataset, ok := graph.Datasets[op.Dataset]
+		if !ok || dataset.Namespace != op.Namespace {
+			hypotheses = append(hypotheses, types.Hypothesis{
+				Confidence: types.ConfidencePodStatusOnly,
+				Severity:   types.SeverityMedium,
+				Component:  "DataOperation",
+				Issue:      fmt.Sprintf("%s targets a Dataset that does not exist", op.Kind),
+				Evidence: []string{fmt.Sprintf("%s %s/%s: Phase=%s, target Dataset %s/%s not found",
+					op.Kind, op.Namespace, name, phaseOrEmpty(op.Phase), op.Namespace, op.Dataset)},
+				Suggestion: fmt.Sprintf("Check spec.dataset of %s %s. The Dataset must exist in the same namespace.", op.Kind, name),
+				Objects:    []types.ObjectReference{datasetRef(op.Namespace, op.Dataset)},
+			})
+			continue
+		}
+		if dataset.Status != "Bound" {
+			hypotheses = append(hypotheses, types.Hypothesis{
+				Confidence: types.ConfidencePodStatusOnly,
+				Severity:   types.SeverityMedium,
+				Component:  "DataOperation",
+				Issue:      fmt.Sprintf("%s is waiting on a Dataset that is not bound", op.Kind),
+				Evidence: []string{
+					fmt.Sprintf("%s %s/%s: Phase=%s", op.Kind, op.Namespace, name, phaseOrEmpty(op.Phase)),
+					fmt.Sprintf("Dataset %s/%s: Status=%s", dataset.Namespace, dataset.Name, phaseOrEmpty(dataset.Status)),
+				},
+				Suggestion: fmt.Sprintf("Fix the Dataset first; %s %s starts once Dataset %s is bound.", op.Kind, name, op.Dataset),
+				Objects:    []types.ObjectReference{datasetRef(op.Namespace, op.Dataset)},
+			})
+			continue
+		}
+
+		// Gather evidence from pending job pods
+		var evidence []string
+		confidence := types.ConfidencePodStatusOnly
+		for _, pod := range idx.JobPods(op) {
+			if pod.Status != "Pending" {
+				continue
+			}
+			evidence = append(evidence, fmt.Sprintf("Pod %s/%s: Status=Pending", pod.Namespace, pod.Name))
+			for _, event := range idx.EventsFor(index.KindPod, pod.Namespace, pod.Name) {
+				if event.Type == "Warning" && event.Reason == "FailedScheduling" {
+					evidence = append(evidence, fmt.Sprintf("Event on pod %s: %s - %s", pod.Name, event.Reason, schedulingBreakdown(event.Message)))
+					confidence = types.ConfidenceEventAndStatus
+				}
+			}
+		}
+		if len(evidence) == 0 {
+			continue
+		}
+
+		hypotheses = append(hypotheses, types.Hypothesis{
+			Confidence: confidence,
+			Severity:   types.SeverityMedium,
+			Component:  "DataOperation",
+			Issue:      fmt.Sprintf("%s job pods cannot be scheduled", op.Kind),
+			Evidence: append([]string{fmt.Sprintf("%s %s/%s: Phase=%s",
+				op.Kind, op.Namespace, name, phaseOrEmpty(op.Phase))}, evidence...),
+			Suggestion: fmt.Sprintf("Check the scheduling events of the %s job pods. Adjust the operation's nodeSelector, tolerations or resource requests, or free capacity on the nodes.", op.Kind),
+			Objects:    []types.ObjectReference{datasetRef(op.Namespace, op.Dataset)},
+		})
+	}
+
+	return hypotheses
+}
+
+// phaseOrEmpty renders an unset phase or status as "<empty>".
+func phaseOrEmpty(phase string) string {
+	if phase == "" {
+		return "<empty>"
+	}
+	return phase
+}
@@ -0,0 +1,122 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/scheduling"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// runtimePods returns the pods with the given role that belong to runtime,
+// identified by the Fluid "release" label or by the StatefulSet/DaemonSet
+// named after the runtime.
+func runtimePods(idx *index.Index, runtime types.RuntimeInfo, role roles.Role) []types.PodInfo {
+	var pods []types.PodInfo
+	for _, pod := range idx.PodsByRole(role) {
+		if pod.Namespace != runtime.Namespace {
+			continue
+		}
+		if pod.Labels["release"] == runtime.Name {
+			pods = append(pods, pod)
+			continue
+		}
+		for _, owner := range pod.OwnerReferences {
+			if owner.Name == runtime.Name+"-"+string(role) {
+				pods = append(pods, pod)
+				break
+			}
+		}
+	}
+	return pods
+}
+
+// datasetRef refers to the Dataset namespace/name. Fluid gives a Runtime and
+// the Dataset's PVC the same name as the Dataset.
+func datasetRef(namespace, name string) types.ObjectReference {
+	return types.ObjectReference{Kind: index.KindDataset, Namespace: namespace, Name: name}
+}
+
+// pvcRef refers to the PVC namespace/name.
+func pvcRef(namespace, name string) types.ObjectReference {
+	return types.ObjectReference{Kind: index.KindPVC, Namespace: namespace, Name: name}
+}
+
+// appendObject appends ref to objects unless it is already there.
+func appendObject(objects []types.ObjectReference, ref types.ObjectReference) []types.ObjectReference {
+	if slices.Contains(objects, ref) {
+		return objects
+	}
+	return append(objects, ref)
+}
+
+// schedulingBreakdown renders a scheduler message as its per-reason node
+// counts, or returns it unchanged when it is not in the scheduler's format.
+func schedulingBreakdown(message string) string {
+	if m, ok := scheduling.Parse(message); ok {
+		return m.String()
+	}
+	return message
+}
+
+// podDataset returns the Dataset a Fluid pod with the given role serves,
+// from its "release" label or from the StatefulSet/DaemonSet that owns it.
+func podDataset(pod types.PodInfo, role roles.Role) (types.ObjectReference, bool) {
+	if release := pod.Labels["release"]; release != "" {
+		return datasetRef(pod.Namespace, release), true
+	}
+	for _, owner := range pod.OwnerReferences {
+		if name, ok := strings.CutSuffix(owner.Name, "-"+string(role)); ok && name != "" {
+			return datasetRef(pod.Namespace, name), true
+		}
+	}
+	return types.ObjectReference{}, false
+}
+
+// podProblems describes why a pod is not healthy, one entry per symptom.
+// It returns nil for a Running pod whose containers are all ready.
+func podProblems(pod types.PodInfo) []string {
+	var problems []string
+	if pod.Status != "Running" {
+		problems = append(problems, fmt.Sprintf("Status=%s", pod.Status))
+	}
+	for _, cs := range pod.ContainerStatuses {
+		if cs.Ready {
+			continue
+		}
+		desc := fmt.Sprintf("container %s not ready", cs.Name)
+		if cs.Reason != "" {
+			desc += fmt.Sprintf(", reason=%s", cs.Reason)
+		}
+		if cs.RestartCount > 0 {
+			desc += fmt.Sprintf(", restarts=%d", cs.RestartCount)
+		}
+		if cs.LastTerminationReason != "" {
+			desc += fmt.Sprintf(", lastTermination=%s", cs.LastTerminationReason)
+		}
+		problems = append(problems, desc)
+	}
+	return problems
+}
+
+// matchLogLines returns the lines of log containing any of keywords
+// (case-insensitive), in order, keeping at most limit lines.
+func matchLogLines(log string, keywords []string, limit int) []string {
+	var lines []string
+	for _, line := range strings.Split(log, "\n") {
+		lower := strings.ToLower(line)
+		for _, kw := range keywords {
+			if strings.Contains(lower, kw) {
+				lines = append(lines, strings.TrimSpace(line))
+				break
+			}
+		}
+		if len(lines) == limit {
+			break
+		}
+	}
+	return lines
+}
@@ -0,0 +1,101 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// journalKeywords identify master log lines about journal or format failures.
+var journalKeywords = []string{"journal", "not formatted", "format failed", "failed to format"}
+
+// leaderKeywords identify master log lines about HA and leader election.
+var leaderKeywords = []string{"leader election", "leadership", "not the leader", "no leader", "raft", "zookeeper"}
+
+// MasterNotReadyRule detects Runtime masters that are not ready. A dead
+// master blocks the entire Dataset, so this is reported separately from
+// worker shortfalls and at critical severity. The Runtime status alone is a
+// status signal; master pod, event or log evidence corroborates it, so the
+// hypothesis ranks with worker hypotheses backed by the same evidence.
+type MasterNotReadyRule struct{}
+
+func (r *MasterNotReadyRule) ID() string {
+	return "master-not-ready"
+}
+
+func (r *MasterNotReadyRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var hypotheses []types.Hypothesis
+
+	for _, name := range idx.RuntimeNames() {
+		runtime := idx.Context().Graph.Runtimes[name]
+		if runtime.MasterReplicas == 0 || runtime.MasterReady >= runtime.MasterReplicas {
+			continue
+		}
+
+		evidence := []string{fmt.Sprintf("Runtime %s/%s: Master %d/%d ready",
+			runtime.Namespace, name, runtime.MasterReady, runtime.MasterReplicas)}
+		confidence := types.ConfidencePodStatusOnly
+		podSignal := false
+
+		// Gather evidence from master pods and their events
+		var logKeys []string
+		for _, pod := range runtimePods(idx, runtime, roles.Master) {
+			logKeys = append(logKeys, pod.Name)
+			for _, problem := range podProblems(pod) {
+				evidence = append(evidence, fmt.Sprintf("Pod %s/%s: %s", pod.Namespace, pod.Name, problem))
+				podSignal = true
+			}
+			for _, event := range idx.EventsFor(index.KindPod, pod.Namespace, pod.Name) {
+				if event.Type == "Warning" {
+					evidence = append(evidence, fmt.Sprintf("Event on pod %s: %s - %s", pod.Name, event.Reason, event.Message))
+					podSignal = true
+				}
+			}
+		}
+
+		// Gather evidence from master logs
+		logKeys = append(logKeys, strings.ToLower(runtime.Type)+"-master")
+		var journal, leader bool
+		for _, key := range logKeys {
+			log, ok := idx.Context().Logs[key]
+			if !ok {
+				continue
+			}
+			for _, line := range matchLogLines(log, journalKeywords, 3) {
+				evidence = append(evidence, fmt.Sprintf("Log %s: %s", key, line))
+				journal = true
+			}
+			for _, line := range matchLogLines(log, leaderKeywords, 3) {
+				evidence = append(evidence, fmt.Sprintf("Log %s: %s", key, line))
+				leader = true
+			}
+		}
+		if podSignal || journal || leader {
+			confidence = types.ConfidenceEventAndStatus
+		}
+
+		h := types.Hypothesis{
+			Confidence: confidence,
+			Severity:   types.SeverityCritical,
+			Component:  "Master",
+			Issue:      "Runtime master is not ready, blocking the entire Dataset",
+			Evidence:   evidence,
+			Suggestion: "Check the master pod status, events and logs. Until the master recovers, no worker or Fuse pod can serve the Dataset.",
+			Objects:    []types.ObjectReference{datasetRef(runtime.Namespace, name)},
+		}
+		switch {
+		case journal:
+			h.Issue = "Runtime master is failing on journal or format errors, blocking the entire Dataset"
+			h.Suggestion = "Inspect the master journal storage (PVC or hostPath) for corruption or missing format. Restore or re-format the journal only after backing up metadata."
+		case leader:
+			h.Issue = "Runtime master cannot establish HA leadership, blocking the entire Dataset"
+			h.Suggestion = "Check connectivity between master replicas and the HA backend (embedded Raft or ZooKeeper). Ensure a quorum of masters is running."
+		}
+		hypotheses = append(hypotheses, h)
+	}
+
+	return hypotheses
+}
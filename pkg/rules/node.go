@@ -0,0 +1,177 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// pressureConditions are the node conditions that make the kubelet evict pods.
+var pressureConditions = []string{"MemoryPressure", "DiskPressure", "PIDPressure"}
+
+// evictionPressure maps the resource in a kubelet eviction message to the
+// node condition it raises.
+var evictionPressure = map[string]string{
+	"memory":            "MemoryPressure",
+	"ephemeral-storage": "DiskPressure",
+	"nodefs":            "DiskPressure",
+	"imagefs":           "DiskPressure",
+	"pids":              "PIDPressure",
+}
+
+// evicted reports whether pod was evicted by the kubelet.
+func evicted(pod types.PodInfo) bool {
+	return pod.Status == "Failed" && pod.Reason == "Evicted"
+}
+
+// evictionCondition returns the pressure condition named by an eviction
+// message such as "The node was low on resource: ephemeral-storage.".
+func evictionCondition(message string) string {
+	_, resource, ok := strings.Cut(message, "low on resource: ")
+	if !ok {
+		return ""
+	}
+	resource = strings.TrimRight(strings.Fields(resource + " ")[0], ".,")
+	return evictionPressure[resource]
+}
+
+// NodePressureRule detects evicted Fluid pods and resource pressure on the
+// nodes hosting Fluid pods. Under DiskPressure, disk tiers of the Runtimes
+// caching on the node are cited as a probable cause.
+type NodePressureRule struct{}
+
+func (r *NodePressureRule) ID() string {
+	return "node-pressure"
+}
+
+func (r *NodePressureRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var hypotheses []types.Hypothesis
+	graph := idx.Context().Graph
+
+	for _, nodeName := range idx.NodeNames() {
+		var fluidPods []types.PodInfo
+		for _, pod := range idx.PodsOnNode(nodeName) {
+			if idx.Role(pod.Name) != roles.Unknown {
+				fluidPods = append(fluidPods, pod)
+			}
+		}
+		if len(fluidPods) == 0 {
+			continue
+		}
+
+		var evidence []string
+		pressures := map[string]bool{}
+		for _, cond := range graph.Nodes[nodeName].Conditions {
+			for _, pressure := range pressureConditions {
+				if cond.Type == pressure && cond.Status == "True" {
+					evidence = append(evidence, fmt.Sprintf("Node %s: Condition %s=True, reason=%s", nodeName, cond.Type, cond.Reason))
+					pressures[pressure] = true
+				}
+			}
+		}
+		condition := len(evidence) > 0
+
+		// Gather evicted Fluid pods and their Evicted events
+		var objects []types.ObjectReference
+		objects = append(objects, types.ObjectReference{Kind: index.KindNode, Name: nodeName})
+		var evictions, events bool
+		for _, pod := range fluidPods {
+			if !evicted(pod) {
+				continue
+			}
+			evidence = append(evidence, fmt.Sprintf("Pod %s/%s (%s): Evicted - %s", pod.Namespace, pod.Name, idx.Role(pod.Name), pod.Message))
+			if pressure := evictionCondition(pod.Message); pressure != "" {
+				pressures[pressure] = true
+			}
+			for _, event := range idx.EventsFor(index.KindPod, pod.Namespace, pod.Name) {
+				if event.Type == "Warning" && event.Reason == "Evicted" {
+					evidence = append(evidence, fmt.Sprintf("Event on pod %s: %s - %s", pod.Name, event.Reason, event.Message))
+					events = true
+				}
+			}
+			if ref, ok := podDataset(pod, idx.Role(pod.Name)); ok {
+				objects = appendObject(objects, ref)
+			}
+			evictions = true
+		}
+		if len(evidence) == 0 {
+			continue
+		}
+
+		confidence := types.ConfidenceConditionOnly
+		switch {
+		case evictions && (condition || events):
+			confidence = types.ConfidenceEventAndStatus
+		case evictions:
+			confidence = types.ConfidencePodStatusOnly
+		}
+
+		// Disk tiers of the Runtimes with workers here are a probable cause
+		diskCache := false
+		if pressures["DiskPressure"] {
+			for _, name := range idx.RuntimeNames() {
+				runtime := graph.Runtimes[name]
+				if !runsOnNode(runtimePods(idx, runtime, roles.Worker), nodeName) {
+					continue
+				}
+				for i, level := range runtime.TieredStore {
+					if level.MediumType == "MEM" {
+						continue
+					}
+					evidence = append(evidence, fmt.Sprintf("Runtime %s/%s tier %d (%s, %s): caches up to %s per worker on node-local disk",
+						runtime.Namespace, name, i, level.MediumType, level.Path, level.Quota))
+					diskCache = true
+				}
+			}
+		}
+
+		h := types.Hypothesis{
+			Confidence: confidence,
+			Severity:   types.SeverityMedium,
+			Component:  "Node",
+			Issue:      fmt.Sprintf("Node is under %s, putting the Fluid pods on it at risk of eviction", pressureList(pressures)),
+			Evidence:   evidence,
+			Suggestion: "Free the resource on the node or move Fluid pods away from it. Check which pods consume it with kubectl top or du on the node.",
+			Objects:    objects,
+		}
+		if evictions {
+			h.Severity = types.SeverityHigh
+			h.Issue = "Node evicted Fluid pods"
+			if len(pressures) > 0 {
+				h.Issue += " under " + pressureList(pressures)
+			}
+		}
+		if diskCache {
+			h.Issue += "; the Runtime's disk cache is a probable cause"
+			h.Suggestion = "Lower the quota of the disk tiers, move them to a dedicated disk that is not the kubelet's root filesystem, or raise the kubelet eviction thresholds. Cached blocks count as node disk usage."
+		}
+		hypotheses = append(hypotheses, h)
+	}
+
+	return hypotheses
+}
+
+// runsOnNode reports whether any of pods is scheduled onto node.
+func runsOnNode(pods []types.PodInfo, node string) bool {
+	for _, pod := range pods {
+		if pod.NodeName == node {
+			return true
+		}
+	}
+	return false
+}
+
+// pressureList renders the set pressure conditions, e.g. "DiskPressure and
+// MemoryPressure", in a fixed order.
+func pressureList(pressures map[string]bool) string {
+	var names []string
+	for _, p := range pressureConditions {
+		if pressures[p] {
+			names = append(names, p)
+		}
+	}
+	return strings.Join(names, " and ")
+}
@@ -3,52 +3,33 @@ package rules
 import (
 	"fmt"
 
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
 	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
 )
 
 // RuntimePartiallyReadyRule detects runtimes that are only partially ready due to dependency failures.
+// Master shortfalls are reported separately by MasterNotReadyRule.
 type RuntimePartiallyReadyRule struct{}
 
 func (r *RuntimePartiallyReadyRule) ID() string {
 	return "runtime-partially-ready"
 }
 
-func (r *RuntimePartiallyReadyRule) Match(ctx types.DiagnosticContext) bool {
-	for _, runtime := range ctx.Graph.Runtimes {
-		// Check if master or worker replicas are not fully ready
-		if runtime.MasterReplicas > 0 && runtime.MasterReady < runtime.MasterReplicas {
-			return true
-		}
-		if runtime.WorkerReplicas > 0 && runtime.WorkerReady < runtime.WorkerReplicas {
-			return true
-		}
-		// Check for non-ready conditions
-		for _, cond := range runtime.Conditions {
-			if cond.Status == "False" && cond.Type == "Ready" {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-func (r *RuntimePartiallyReadyRule) Hypothesis(ctx types.DiagnosticContext) types.Hypothesis {
+func (r *RuntimePartiallyReadyRule) Evaluate(idx *index.Index) []types.Hypothesis {
 	var evidence []string
+	var objects []types.ObjectReference
 	confidence := types.ConfidenceConditionOnly
 
-	for name, runtime := range ctx.Graph.Runtimes {
-		if runtime.MasterReplicas > 0 && runtime.MasterReady < runtime.MasterReplicas {
-			evidence = append(evidence, fmt.Sprintf("Runtime %s/%s: Master %d/%d ready",
-				runtime.Namespace, name, runtime.MasterReady, runtime.MasterReplicas))
-			confidence = types.ConfidencePodStatusOnly
-		}
+	for _, name := range idx.RuntimeNames() {
+		runtime := idx.Context().Graph.Runtimes[name]
+		before := len(evidence)
 		if runtime.WorkerReplicas > 0 && runtime.WorkerReady < runtime.WorkerReplicas {
 			evidence = append(evidence, fmt.Sprintf("Runtime %s/%s: Worker %d/%d ready",
 				runtime.Namespace, name, runtime.WorkerReady, runtime.WorkerReplicas))
 			confidence = types.ConfidencePodStatusOnly
 		}
 		for _, cond := range runtime.Conditions {
-			if cond.Status == "False" {
+			if cond.Type == "Ready" && cond.Status == "False" {
 				evidence = append(evidence, fmt.Sprintf("Runtime %s/%s: Condition %s=%s, reason=%s",
 					runtime.Namespace, name, cond.Type, cond.Status, cond.Reason))
 				if cond.Reason != "" {
@@ -56,13 +37,22 @@ func (r *RuntimePartiallyReadyRule) Hypothesis(ctx types.DiagnosticContext) type
 				}
 			}
 		}
+		if len(evidence) > before {
+			objects = append(objects, datasetRef(runtime.Namespace, name))
+		}
 	}
 
-	return types.Hypothesis{
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	return []types.Hypothesis{{
 		Confidence: confidence,
+		Severity:   types.SeverityMedium,
 		Component:  "Runtime",
 		Issue:      "Runtime is only partially ready, indicating dependency or configuration failure",
 		Evidence:   evidence,
 		Suggestion: "Check runtime pod logs for errors. Verify storage backend connectivity and credentials. Ensure all required dependencies are available.",
-	}
+		Objects:    objects,
+	}}
 }
@@ -0,0 +1,233 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/version"
+)
+
+// mountNotReady are log fragments of an application that touched the Fuse
+// mount point before the sidecar had mounted it.
+var mountNotReady = []string{
+	"transport endpoint is not connected",
+	"no such file or directory",
+	"is not a mountpoint",
+	"not mounted",
+}
+
+// nativeSidecars is the first Kubernetes release with native sidecar
+// containers enabled by default.
+var nativeSidecars = version.MustParseConstraint(">=1.29")
+
+// sidecarDatasets returns the Datasets whose PVCs pod mounts. Fluid names a
+// Dataset's PVC after the Dataset.
+func sidecarDatasets(idx *index.Index, pod types.PodInfo, objects []types.ObjectReference) []types.ObjectReference {
+	for _, claim := range pod.PVCs {
+		if dataset, ok := idx.Context().Graph.Datasets[claim]; ok && dataset.Namespace == pod.Namespace {
+			objects = appendObject(objects, datasetRef(pod.Namespace, claim))
+		}
+	}
+	return objects
+}
+
+// containerNames lists the names of pod's containers.
+func containerNames(pod types.PodInfo) []string {
+	var names []string
+	for _, cs := range pod.ContainerStatuses {
+		names = append(names, cs.Name)
+	}
+	return names
+}
+
+// FuseSidecarNotInjectedRule detects pods that ask for Fuse sidecar injection
+// but started without one, usually because the webhook was unavailable.
+type FuseSidecarNotInjectedRule struct{}
+
+func (r *FuseSidecarNotInjectedRule) ID() string {
+	return "fuse-sidecar-not-injected"
+}
+
+func (r *FuseSidecarNotInjectedRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var evidence []string
+	var objects []types.ObjectReference
+	for _, pod := range idx.FuseSidecarPods() {
+		// Pods that have not started yet may still be injected on retry
+		if !index.FuseInjectionRequested(pod) || len(pod.ContainerStatuses) == 0 {
+			continue
+		}
+		injected := false
+		for _, cs := range pod.ContainerStatuses {
+			injected = injected || index.IsFuseSidecar(cs.Name)
+		}
+		if injected {
+			continue
+		}
+		evidence = append(evidence, fmt.Sprintf("Pod %s/%s: requests Fuse injection (%s=true) but runs only [%s]",
+			pod.Namespace, pod.Name, index.FuseInjectLabel, strings.Join(containerNames(pod), ", ")))
+		objects = sidecarDatasets(idx, pod, objects)
+	}
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	// The webhook performs the injection
+	confidence := types.ConfidencePodStatusOnly
+	webhook, _ := controlPlaneEvidence(idx, roles.Webhook)
+	if len(idx.ControlPlane()) > 0 && !hasControlPlaneRole(idx, roles.Webhook) {
+		webhook = append(webhook, fmt.Sprintf("Control plane: no webhook pods among %d collected pods", len(idx.ControlPlane())))
+	}
+	if len(webhook) > 0 {
+		evidence = append(evidence, webhook...)
+		confidence = types.ConfidenceEventAndStatus
+	}
+
+	return []types.Hypothesis{{
+		Confidence: confidence,
+		Severity:   types.SeverityHigh,
+		Component:  "FuseSidecar",
+		Issue:      "Pods requesting a Fuse sidecar were started without one, so the Dataset is not mounted in them",
+		Evidence:   evidence,
+		Suggestion: "Make sure the Fluid webhook is running and its MutatingWebhookConfiguration covers the pods' namespace, then recreate the pods; injection only happens at pod creation.",
+		Objects:    objects,
+	}}
+}
+
+// FuseSidecarCrashRule detects injected Fuse sidecars that are crash-looping
+// or otherwise not ready.
+type FuseSidecarCrashRule struct{}
+
+func (r *FuseSidecarCrashRule) ID() string {
+	return "fuse-sidecar-crash"
+}
+
+func (r *FuseSidecarCrashRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var evidence []string
+	var objects []types.ObjectReference
+	confidence := types.ConfidencePodStatusOnly
+
+	for _, pod := range idx.FuseSidecarPods() {
+		for _, cs := range pod.ContainerStatuses {
+			if !index.IsFuseSidecar(cs.Name) || cs.Ready {
+				continue
+			}
+			desc := fmt.Sprintf("Pod %s/%s: sidecar %s not ready", pod.Namespace, pod.Name, cs.Name)
+			if cs.Reason != "" {
+				desc += fmt.Sprintf(", reason=%s", cs.Reason)
+			}
+			if cs.RestartCount > 0 {
+				desc += fmt.Sprintf(", restarts=%d", cs.RestartCount)
+			}
+			if cs.LastTerminationReason != "" {
+				desc += fmt.Sprintf(", lastTermination=%s", cs.LastTerminationReason)
+			}
+			evidence = append(evidence, desc)
+			objects = sidecarDatasets(idx, pod, objects)
+
+			for _, event := range idx.EventsFor(index.KindPod, pod.Namespace, pod.Name) {
+				if event.Type == "Warning" && strings.Contains(event.Message, cs.Name) {
+					evidence = append(evidence, fmt.Sprintf("Event on pod %s: %s - %s", pod.Name, event.Reason, event.Message))
+					confidence = types.ConfidenceEventAndStatus
+				}
+			}
+			for _, line := range matchLogLines(idx.Context().Logs[pod.Name], []string{"fuse", "mount"}, 3) {
+				evidence = append(evidence, fmt.Sprintf("Log %s: %s", pod.Name, line))
+			}
+		}
+	}
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	return []types.Hypothesis{{
+		Confidence: confidence,
+		Severity:   types.SeverityHigh,
+		Component:  "FuseSidecar",
+		Issue:      "Injected Fuse sidecar is not running, so the application cannot read the Dataset",
+		Evidence:   evidence,
+		Suggestion: "Inspect the sidecar logs (kubectl logs <pod> -c fluid-fuse-0 --previous). Check that the Runtime is ready, that the sidecar has enough memory, and that privileged containers are allowed in the namespace.",
+		Objects:    objects,
+	}}
+}
+
+// FuseSidecarStartupOrderRule detects application containers that started
+// before the injected Fuse sidecar had mounted the Dataset and failed on the
+// missing mount. A restart alone is not enough: the pod's logs or events must
+// show the missing mount.
+type FuseSidecarStartupOrderRule struct{}
+
+func (r *FuseSidecarStartupOrderRule) ID() string {
+	return "fuse-sidecar-startup-order"
+}
+
+func (r *FuseSidecarStartupOrderRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var evidence []string
+	var objects []types.ObjectReference
+	confidence := types.ConfidenceLogMatch
+
+	for _, pod := range idx.FuseSidecarPods() {
+		// A crashed sidecar is fuse-sidecar-crash's to report
+		sidecarReady := false
+		var restarted []types.ContainerStatus
+		for _, cs := range pod.ContainerStatuses {
+			switch {
+			case index.IsFuseSidecar(cs.Name):
+				sidecarReady = cs.Ready
+			case cs.RestartCount > 0:
+				restarted = append(restarted, cs)
+			}
+		}
+		if !sidecarReady || len(restarted) == 0 {
+			continue
+		}
+
+		lines := matchLogLines(idx.Context().Logs[pod.Name], mountNotReady, 3)
+		var events []types.Event
+		for _, event := range idx.EventsFor(index.KindPod, pod.Namespace, pod.Name) {
+			if event.Type == "Warning" && len(matchLogLines(event.Message, mountNotReady, 1)) > 0 {
+				events = append(events, event)
+			}
+		}
+		if len(lines) == 0 && len(events) == 0 {
+			continue
+		}
+
+		for _, cs := range restarted {
+			evidence = append(evidence, fmt.Sprintf("Pod %s/%s: container %s restarted %d time(s) while the Fuse sidecar is ready",
+				pod.Namespace, pod.Name, cs.Name, cs.RestartCount))
+		}
+		for _, line := range lines {
+			evidence = append(evidence, fmt.Sprintf("Log %s: %s", pod.Name, line))
+		}
+		for _, event := range events {
+			evidence = append(evidence, fmt.Sprintf("Event on pod %s: %s - %s", pod.Name, event.Reason, event.Message))
+			confidence = types.ConfidenceEventAndStatus
+		}
+		objects = sidecarDatasets(idx, pod, objects)
+	}
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	suggestion := "Make the application wait for the mount before reading, e.g. by checking the mount point in its entrypoint."
+	if v, ok := idx.ClusterVersion(); ok {
+		if nativeSidecars.Check(v) {
+			suggestion = fmt.Sprintf("Kubernetes %s supports native sidecar containers: inject Fuse as a native sidecar so the Dataset is mounted before application containers start.", v)
+		} else {
+			suggestion = fmt.Sprintf("Kubernetes %s has no native sidecar containers, so containers start in parallel. Make the application wait for the mount, or upgrade to 1.29+ and inject Fuse as a native sidecar.", v)
+		}
+	}
+
+	return []types.Hypothesis{{
+		Confidence: confidence,
+		Severity:   types.SeverityMedium,
+		Component:  "FuseSidecar",
+		Issue:      "Application containers start before the Fuse sidecar has mounted the Dataset",
+		Evidence:   evidence,
+		Suggestion: suggestion,
+		Objects:    objects,
+	}}
+}
@@ -0,0 +1,136 @@
+package rules
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/version"
+)
+
+// imageVersion returns the version in the tag of a Fluid image, e.g. v1.0.3
+// for "fluidcloudnative/dataset-controller:v1.0.3-a1b2c3d". Images from
+// repositories outside Fluid, such as the CSI node-driver-registrar sidecar,
+// follow their own versioning and are ignored.
+func imageVersion(image string) (version.Version, bool) {
+	image, _, _ = strings.Cut(image, "@")
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return version.Version{}, false
+	}
+	if !strings.Contains(strings.ToLower(image[:i]), "fluid") {
+		return version.Version{}, false
+	}
+	v, err := version.Parse(image[i+1:])
+	return v, err == nil
+}
+
+// controlPlaneComponent names the Deployment or DaemonSet a control-plane
+// pod belongs to.
+func controlPlaneComponent(pod types.PodInfo) string {
+	for _, owner := range pod.OwnerReferences {
+		if i := strings.LastIndex(owner.Name, "-"); owner.Kind == "ReplicaSet" && i > 0 {
+			return owner.Name[:i]
+		}
+		return owner.Name
+	}
+	return pod.Name
+}
+
+// VersionSkewRule detects Fluid control-plane components running different
+// Fluid releases, as left behind by a partial upgrade. The collector has its
+// own versioning; rule-pack selection checks it against CollectorVersions.
+type VersionSkewRule struct{}
+
+func (r *VersionSkewRule) ID() string {
+	return "fluid-version-skew"
+}
+
+func (r *VersionSkewRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	// component -> version -> pods
+	running := map[string]map[version.Version][]string{}
+	counts := map[version.Version]int{}
+	for _, pod := range idx.ControlPlane() {
+		for _, cs := range pod.ContainerStatuses {
+			v, ok := imageVersion(cs.Image)
+			if !ok {
+				continue
+			}
+			component := controlPlaneComponent(pod)
+			if running[component] == nil {
+				running[component] = map[version.Version][]string{}
+			}
+			running[component][v] = append(running[component][v], pod.Name)
+			counts[v]++
+			break
+		}
+	}
+	if len(running) == 0 {
+		return nil
+	}
+
+	// The installed release if reported, otherwise what most pods run
+	expected, ok := fluidVersion(idx)
+	basis := fmt.Sprintf("Fluid %s: reported installed release", expected)
+	if !ok {
+		for v, n := range counts {
+			if n > counts[expected] || (n == counts[expected] && v.Compare(expected) > 0) {
+				expected = v
+			}
+		}
+		basis = fmt.Sprintf("Fluid %s: run by %d of %d control-plane pods", expected, counts[expected], sum(counts))
+	}
+
+	evidence := []string{basis}
+	var outOfLine []string
+	severity := types.SeverityLow
+	for _, component := range slices.Sorted(maps.Keys(running)) {
+		versions := running[component]
+		for _, v := range slices.SortedFunc(maps.Keys(versions), version.Version.Compare) {
+			if v == expected {
+				continue
+			}
+			pods := versions[v]
+			evidence = append(evidence, fmt.Sprintf("Component %s: runs %s on %d pod(s) (%s)",
+				component, v, len(pods), strings.Join(pods, ", ")))
+			if !slices.Contains(outOfLine, component) {
+				outOfLine = append(outOfLine, component)
+			}
+			severity = min(severity, skewSeverity(v, expected))
+		}
+	}
+
+	if len(outOfLine) == 0 {
+		return nil
+	}
+
+	return []types.Hypothesis{{
+		Confidence: types.ConfidencePodStatusOnly,
+		Severity:   severity,
+		Component:  "Controller",
+		Issue:      fmt.Sprintf("Fluid components run different releases: %s out of line with %s", strings.Join(outOfLine, ", "), expected),
+		Evidence:   evidence,
+		Suggestion: fmt.Sprintf("Finish the upgrade so every component runs %s: re-run the Helm upgrade and check the rollout of the cited Deployments and the CSI DaemonSet.", expected),
+	}}
+}
+
+// skewSeverity rates running v where expected is the installed release: a
+// different minor release may not understand the CRDs, a patch skew rarely
+// matters.
+func skewSeverity(v, expected version.Version) int {
+	if v.Major != expected.Major || v.Minor != expected.Minor {
+		return types.SeverityHigh
+	}
+	return types.SeverityMedium
+}
+
+func sum(counts map[version.Version]int) int {
+	n := 0
+	for _, c := range counts {
+		n += c
+	}
+	return n
+}
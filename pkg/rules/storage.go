@@ -2,74 +2,79 @@ package rules
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
 	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
 )
 
-// PVCUnboundRule detects PVCs that are not bound due to storage provisioning issues.
+// pvcFailureReasons are the event reasons reported when a PVC cannot be provisioned or bound.
+var pvcFailureReasons = []string{"ProvisioningFailed", "FailedBinding"}
+
+// PVCUnboundRule detects PVCs that are not bound due to storage provisioning
+// issues. Pending PVCs that a more specific storage rule explains are left to
+// that rule.
 type PVCUnboundRule struct{}
 
 func (r *PVCUnboundRule) ID() string {
 	return "pvc-unbound"
 }
 
-func (r *PVCUnboundRule) Match(ctx types.DiagnosticContext) bool {
-	for _, pvc := range ctx.Graph.PVCs {
-		if pvc.Status == "Pending" || pvc.Status == "Lost" {
-			return true
-		}
-	}
-
-	// Check for provisioning failure events
-	for _, event := range ctx.Events {
-		if event.Type == "Warning" && event.InvolvedObject.Kind == "PersistentVolumeClaim" {
-			if strings.Contains(event.Reason, "ProvisioningFailed") ||
-				strings.Contains(event.Reason, "FailedBinding") {
-				return true
-			}
-		}
-	}
-
-	return false
-}
-
-func (r *PVCUnboundRule) Hypothesis(ctx types.DiagnosticContext) types.Hypothesis {
+func (r *PVCUnboundRule) Evaluate(idx *index.Index) []types.Hypothesis {
 	var evidence []string
+	var objects []types.ObjectReference
 	confidence := types.ConfidenceConditionOnly
+	graph := idx.Context().Graph
+	classes := collectStorageClasses(idx)
 
-	for name, pvc := range ctx.Graph.PVCs {
-		if pvc.Status == "Pending" {
+	for _, name := range idx.PVCNames() {
+		pvc := graph.PVCs[name]
+		if pvc.Status == "Pending" && !classes.explained(idx, pvc) {
 			evidence = append(evidence, fmt.Sprintf("PVC %s/%s: Status=Pending",
 				pvc.Namespace, name))
+			objects = appendObject(objects, pvcRef(pvc.Namespace, name))
 			confidence = types.ConfidencePodStatusOnly
 		}
 		if pvc.Status == "Lost" {
 			evidence = append(evidence, fmt.Sprintf("PVC %s/%s: Status=Lost",
 				pvc.Namespace, name))
+			objects = appendObject(objects, pvcRef(pvc.Namespace, name))
 			confidence = types.ConfidenceEventAndStatus
 		}
 	}
 
 	// Gather evidence from events
-	for _, event := range ctx.Events {
-		if event.Type == "Warning" && event.InvolvedObject.Kind == "PersistentVolumeClaim" {
-			if strings.Contains(event.Reason, "ProvisioningFailed") ||
-				strings.Contains(event.Reason, "FailedBinding") {
-				evidence = append(evidence, fmt.Sprintf("Event on PVC %s: %s - %s",
-					event.InvolvedObject.Name, event.Reason, event.Message))
-				confidence = types.ConfidenceEventAndStatus
+	for _, reason := range pvcFailureReasons {
+		for _, event := range idx.EventsWithReason(reason) {
+			if event.Type != "Warning" || event.InvolvedObject.Kind != index.KindPVC {
+				continue
 			}
+			if pvc, ok := graph.PVCs[event.InvolvedObject.Name]; ok && pvc.Namespace == event.InvolvedObject.Namespace &&
+				pvc.Status == "Pending" && classes.explained(idx, pvc) {
+				continue
+			}
+			evidence = append(evidence, fmt.Sprintf("Event on PVC %s: %s - %s",
+				event.InvolvedObject.Name, event.Reason, event.Message))
+			objects = appendObject(objects, pvcRef(event.InvolvedObject.Namespace, event.InvolvedObject.Name))
+			confidence = types.ConfidenceEventAndStatus
 		}
 	}
 
-	return types.Hypothesis{
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	return []types.Hypothesis{{
 		Confidence: confidence,
+		Severity:   types.SeverityHigh,
 		Component:  "Storage",
 		Issue:      "PVC is not bound due to storage provisioning failure",
 		Evidence:   evidence,
 		Suggestion: "Check storage class configuration and provisioner status. Verify storage backend has available capacity.",
-	}
+		Objects:    objects,
+	}}
 }
 
 // DatasetNotBoundRule detects Datasets that are not bound due to missing Runtime.
@@ -79,25 +84,13 @@ func (r *DatasetNotBoundRule) ID() string {
 	return "dataset-not-bound"
 }
 
-func (r *DatasetNotBoundRule) Match(ctx types.DiagnosticContext) bool {
-	for _, dataset := range ctx.Graph.Datasets {
-		if dataset.Status == "NotBound" || dataset.Status == "" {
-			return true
-		}
-		for _, cond := range dataset.Conditions {
-			if cond.Type == "Ready" && cond.Status == "False" {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-func (r *DatasetNotBoundRule) Hypothesis(ctx types.DiagnosticContext) types.Hypothesis {
+func (r *DatasetNotBoundRule) Evaluate(idx *index.Index) []types.Hypothesis {
 	var evidence []string
+	var objects []types.ObjectReference
 	confidence := types.ConfidenceConditionOnly
 
-	for name, dataset := range ctx.Graph.Datasets {
+	for _, name := range idx.DatasetNames() {
+		dataset := idx.Context().Graph.Datasets[name]
 		if dataset.Status == "NotBound" || dataset.Status == "" {
 			statusStr := dataset.Status
 			if statusStr == "" {
@@ -105,12 +98,14 @@ func (r *DatasetNotBoundRule) Hypothesis(ctx types.DiagnosticContext) types.Hypo
 			}
 			evidence = append(evidence, fmt.Sprintf("Dataset %s/%s: Status=%s",
 				dataset.Namespace, name, statusStr))
+			objects = appendObject(objects, datasetRef(dataset.Namespace, name))
 			confidence = types.ConfidencePodStatusOnly
 		}
 		for _, cond := range dataset.Conditions {
 			if cond.Type == "Ready" && cond.Status == "False" {
 				evidence = append(evidence, fmt.Sprintf("Dataset %s/%s: Condition Ready=%s, reason=%s",
 					dataset.Namespace, name, cond.Status, cond.Reason))
+				objects = appendObject(objects, datasetRef(dataset.Namespace, name))
 				if cond.Reason != "" {
 					confidence = types.ConfidenceEventAndStatus
 				}
@@ -118,11 +113,377 @@ func (r *DatasetNotBoundRule) Hypothesis(ctx types.DiagnosticContext) types.Hypo
 		}
 	}
 
-	return types.Hypothesis{
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	return []types.Hypothesis{{
 		Confidence: confidence,
+		Severity:   types.SeverityHigh,
 		Component:  "Dataset",
 		Issue:      "Dataset is not bound, likely due to missing or failed Runtime",
 		Evidence:   evidence,
 		Suggestion: "Ensure a Runtime (e.g., AlluxioRuntime, JuiceFSRuntime) is created for this Dataset. Check Runtime status for failures.",
+		Objects:    objects,
+	}}
+}
+
+// isFluidPVC reports whether pvc is the claim Fluid creates for the Dataset
+// of the same name.
+func isFluidPVC(idx *index.Index, pvc types.PVCInfo) bool {
+	dataset, ok := idx.Context().Graph.Datasets[pvc.Name]
+	return ok && dataset.Namespace == pvc.Namespace
+}
+
+// storageClasses records which StorageClasses a pending PVC can rely on.
+type storageClasses struct {
+	hasDefault bool
+	// Statically provisioned PVs, including Fluid's, need no StorageClass object
+	static map[string]bool
+}
+
+func collectStorageClasses(idx *index.Index) storageClasses {
+	graph := idx.Context().Graph
+	classes := storageClasses{static: map[string]bool{}}
+	for _, name := range idx.StorageClassNames() {
+		classes.hasDefault = classes.hasDefault || graph.StorageClasses[name].Default
+	}
+	for _, name := range idx.PVNames() {
+		classes.static[graph.PVs[name].StorageClassName] = true
+	}
+	return classes
+}
+
+// missingEvidence returns evidence that pvc is pending on a StorageClass that
+// does not exist, and whether any of it came from a Warning event.
+func (c storageClasses) missingEvidence(idx *index.Index, pvc types.PVCInfo) (evidence []string, events bool) {
+	graph := idx.Context().Graph
+	if pvc.Status != "Pending" || isFluidPVC(idx, pvc) {
+		return nil, false
+	}
+	if graph.StorageClasses != nil {
+		class := pvc.StorageClassName
+		switch {
+		case class != "" && !c.static[class]:
+			if _, ok := graph.StorageClasses[class]; !ok {
+				evidence = append(evidence, fmt.Sprintf("PVC %s/%s: StorageClass %q not found", pvc.Namespace, pvc.Name, class))
+			}
+		case class == "" && pvc.VolumeName == "" && !c.hasDefault:
+			evidence = append(evidence, fmt.Sprintf("PVC %s/%s: no storageClassName and no default StorageClass", pvc.Namespace, pvc.Name))
+		}
+	}
+	for _, event := range idx.EventsFor(index.KindPVC, pvc.Namespace, pvc.Name) {
+		msg := strings.ToLower(event.Message)
+		if event.Type == "Warning" && strings.Contains(msg, "storageclass") && strings.Contains(msg, "not found") {
+			evidence = append(evidence, fmt.Sprintf("Event on PVC %s: %s - %s", pvc.Name, event.Reason, event.Message))
+			events = true
+		}
+	}
+	return evidence, events
+}
+
+// explained reports whether a more specific storage rule accounts for the
+// pending claim pvc.
+func (c storageClasses) explained(idx *index.Index, pvc types.PVCInfo) bool {
+	if evidence, _ := c.missingEvidence(idx, pvc); len(evidence) > 0 {
+		return true
+	}
+	if evidence, _ := accessModeEvidence(idx, pvc); len(evidence) > 0 {
+		return true
+	}
+	if _, ok := blockingPV(idx, pvc); ok {
+		return true
+	}
+	_, ok := missingFluidPV(idx, pvc)
+	return ok
+}
+
+// StorageClassMissingRule detects pending PVCs whose StorageClass does not
+// exist, or which rely on a default StorageClass the cluster does not have.
+type StorageClassMissingRule struct{}
+
+func (r *StorageClassMissingRule) ID() string {
+	return "storageclass-missing"
+}
+
+func (r *StorageClassMissingRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var evidence []string
+	var objects []types.ObjectReference
+	confidence := types.ConfidencePodStatusOnly
+	classes := collectStorageClasses(idx)
+
+	for _, name := range idx.PVCNames() {
+		pvc := idx.Context().Graph.PVCs[name]
+		pvcEvidence, events := classes.missingEvidence(idx, pvc)
+		if len(pvcEvidence) == 0 {
+			continue
+		}
+		evidence = append(evidence, pvcEvidence...)
+		objects = appendObject(objects, pvcRef(pvc.Namespace, name))
+		if events {
+			confidence = types.ConfidenceEventAndStatus
+		}
+	}
+
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	return []types.Hypothesis{{
+		Confidence: confidence,
+		Severity:   types.SeverityHigh,
+		Component:  "Storage",
+		Issue:      "PVC references a StorageClass that does not exist, so no volume is provisioned",
+		Evidence:   evidence,
+		Suggestion: "Create the StorageClass, fix storageClassName on the PVC, or mark a StorageClass as the cluster default.",
+		Objects:    objects,
+	}}
+}
+
+// AccessModeMismatchRule detects pending PVCs whose only candidate PVs do not
+// offer the requested access modes.
+type AccessModeMismatchRule struct{}
+
+func (r *AccessModeMismatchRule) ID() string {
+	return "pvc-access-mode-mismatch"
+}
+
+func (r *AccessModeMismatchRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var evidence []string
+	var objects []types.ObjectReference
+	confidence := types.ConfidencePodStatusOnly
+
+	for _, name := range idx.PVCNames() {
+		pvc := idx.Context().Graph.PVCs[name]
+		pvcEvidence, events := accessModeEvidence(idx, pvc)
+		if len(pvcEvidence) == 0 {
+			continue
+		}
+		evidence = append(evidence, pvcEvidence...)
+		objects = append(objects, pvcRef(pvc.Namespace, name))
+		if events {
+			confidence = types.ConfidenceEventAndStatus
+		}
+	}
+
+	if len(evidence) == 0 {
+		return nil
 	}
+
+	return []types.Hypothesis{{
+		Confidence: confidence,
+		Severity:   types.SeverityHigh,
+		Component:  "Storage",
+		Issue:      "PVC access modes are not offered by any matching PV, so the claim cannot bind",
+		Evidence:   evidence,
+		Suggestion: "Request an access mode the PV supports. Fluid PVs are ReadOnlyMany unless the Dataset's spec.accessModes asks for ReadWriteMany.",
+		Objects:    objects,
+	}}
+}
+
+// accessModeEvidence returns evidence that pvc is pending because no
+// candidate PV offers its access modes, and whether any of it came from a
+// Warning event.
+func accessModeEvidence(idx *index.Index, pvc types.PVCInfo) (evidence []string, events bool) {
+	graph := idx.Context().Graph
+	if pvc.Status != "Pending" || len(pvc.AccessModes) == 0 {
+		return nil, false
+	}
+
+	var candidates []types.PVInfo
+	if pv, ok := graph.PVs[pvc.VolumeName]; ok {
+		candidates = append(candidates, pv)
+	} else if pvc.VolumeName == "" {
+		for _, pvName := range idx.PVNames() {
+			pv := graph.PVs[pvName]
+			if pv.Status == "Available" && pv.StorageClassName == pvc.StorageClassName && labelsMatch(pv.Labels, pvc.Selector) {
+				candidates = append(candidates, pv)
+			}
+		}
+	}
+
+	var mismatched []string
+	for _, pv := range candidates {
+		if !supportsAccessModes(pv.AccessModes, pvc.AccessModes) {
+			mismatched = append(mismatched, fmt.Sprintf("%s %v", pv.Name, pv.AccessModes))
+		}
+	}
+	if len(candidates) == 0 || len(mismatched) < len(candidates) {
+		return nil, false
+	}
+	evidence = append(evidence, fmt.Sprintf("PVC %s/%s: requests %v, but PV %s",
+		pvc.Namespace, pvc.Name, pvc.AccessModes, strings.Join(mismatched, ", PV ")))
+
+	for _, event := range idx.EventsFor(index.KindPVC, pvc.Namespace, pvc.Name) {
+		if event.Type == "Warning" && strings.Contains(strings.ToLower(event.Message), "accessmode") {
+			evidence = append(evidence, fmt.Sprintf("Event on PVC %s: %s - %s", pvc.Name, event.Reason, event.Message))
+			events = true
+		}
+	}
+	return evidence, events
+}
+
+// supportsAccessModes reports whether offered contains every mode in requested.
+func supportsAccessModes(offered, requested []string) bool {
+	for _, mode := range requested {
+		if !slices.Contains(offered, mode) {
+			return false
+		}
+	}
+	return true
+}
+
+// labelsMatch reports whether labels satisfy a matchLabels selector.
+func labelsMatch(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// blockingPV returns the Released or Failed PV that the pending claim pvc
+// waits for.
+func blockingPV(idx *index.Index, pvc types.PVCInfo) (types.PVInfo, bool) {
+	if pvc.Status != "Pending" || pvc.VolumeName == "" {
+		return types.PVInfo{}, false
+	}
+	pv, ok := idx.Context().Graph.PVs[pvc.VolumeName]
+	return pv, ok && (pv.Status == "Released" || pv.Status == "Failed")
+}
+
+// PVReleasedRule detects PVs left Released or Failed, which no claim can
+// bind to until they are cleaned up. Only PVs that a pending claim waits for,
+// or that were bound to a Dataset's PVC, are reported; other leftover PVs in
+// the cluster are not the Datasets' problem.
+type PVReleasedRule struct{}
+
+func (r *PVReleasedRule) ID() string {
+	return "pv-released-or-failed"
+}
+
+func (r *PVReleasedRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var evidence []string
+	var objects []types.ObjectReference
+	confidence := types.ConfidenceConditionOnly
+	severity := types.SeverityMedium
+	graph := idx.Context().Graph
+
+	for _, name := range idx.PVNames() {
+		pv := graph.PVs[name]
+		if pv.Status != "Released" && pv.Status != "Failed" {
+			continue
+		}
+
+		// A pending claim waiting for this PV is blocked by it
+		var waiting []string
+		for _, pvcName := range idx.PVCNames() {
+			pvc := graph.PVCs[pvcName]
+			if blocking, ok := blockingPV(idx, pvc); ok && blocking.Name == name {
+				waiting = append(waiting, fmt.Sprintf("PVC %s/%s: Status=Pending, waiting for PV %s", pvc.Namespace, pvcName, name))
+				objects = appendObject(objects, pvcRef(pvc.Namespace, pvcName))
+				confidence = types.ConfidencePodStatusOnly
+				severity = types.SeverityHigh
+			}
+		}
+		fluid := false
+		if ref := pv.ClaimRef; ref != nil {
+			if dataset, ok := graph.Datasets[ref.Name]; ok && dataset.Namespace == ref.Namespace {
+				objects = appendObject(objects, datasetRef(ref.Namespace, ref.Name))
+				fluid = true
+			}
+		}
+		if len(waiting) == 0 && !fluid {
+			continue
+		}
+
+		desc := fmt.Sprintf("PV %s: Status=%s", name, pv.Status)
+		if pv.ClaimRef != nil {
+			desc += fmt.Sprintf(", claimRef=%s/%s", pv.ClaimRef.Namespace, pv.ClaimRef.Name)
+		}
+		if pv.Message != "" {
+			desc += ", message=" + pv.Message
+		}
+		evidence = append(evidence, desc)
+		evidence = append(evidence, waiting...)
+	}
+
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	return []types.Hypothesis{{
+		Confidence: confidence,
+		Severity:   severity,
+		Component:  "Storage",
+		Issue:      "PersistentVolume is Released or Failed and cannot be bound again",
+		Evidence:   evidence,
+		Suggestion: "Delete the PV (and recreate the Dataset for Fluid PVs), or clear spec.claimRef if the data must be kept. Released PVs still reference their old claim.",
+		Objects:    objects,
+	}}
+}
+
+// FluidPVMissingRule detects PVCs of bound Datasets that wait for a
+// PersistentVolume the dataset controller never created.
+type FluidPVMissingRule struct{}
+
+func (r *FluidPVMissingRule) ID() string {
+	return "fluid-pv-missing"
+}
+
+// missingFluidPV returns the name of the PV that Fluid should have created
+// for the pending claim pvc of a bound Dataset, if it does not exist.
+func missingFluidPV(idx *index.Index, pvc types.PVCInfo) (string, bool) {
+	graph := idx.Context().Graph
+	// Without collected PVs a missing PV cannot be told apart from an uncollected one
+	if graph.PVs == nil {
+		return "", false
+	}
+	if pvc.Status != "Pending" || !isFluidPVC(idx, pvc) || graph.Datasets[pvc.Name].Status != "Bound" {
+		return "", false
+	}
+	pvName := pvc.VolumeName
+	if pvName == "" {
+		pvName = pvc.Namespace + "-" + pvc.Name
+	}
+	_, ok := graph.PVs[pvName]
+	return pvName, !ok
+}
+
+func (r *FluidPVMissingRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	graph := idx.Context().Graph
+
+	var evidence []string
+	var objects []types.ObjectReference
+	for _, name := range idx.PVCNames() {
+		pvc := graph.PVCs[name]
+		pvName, ok := missingFluidPV(idx, pvc)
+		if !ok {
+			continue
+		}
+		evidence = append(evidence, fmt.Sprintf("PVC %s/%s: Status=Pending, Dataset is Bound, but PV %s does not exist",
+			pvc.Namespace, name, pvName))
+		objects = append(objects, datasetRef(pvc.Namespace, name))
+	}
+
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	confidence := types.ConfidencePodStatusOnly
+	if controllerEvidence, _ := controlPlaneEvidence(idx, roles.Controller); len(controllerEvidence) > 0 {
+		evidence = append(evidence, controllerEvidence...)
+		confidence = types.ConfidenceEventAndStatus
+	}
+
+	return []types.Hypothesis{{
+		Confidence: confidence,
+		Severity:   types.SeverityHigh,
+		Component:  "Storage",
+		Issue:      "Fluid did not create the PersistentVolume for the Dataset, so its PVC cannot bind",
+		Evidence:   evidence,
+		Suggestion: "Check the dataset controller logs for errors creating the PV. Deleting the PVC lets the controller recreate both the PV and the PVC.",
+		Objects:    objects,
+	}}
 }
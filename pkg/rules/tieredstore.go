@@ -0,0 +1,148 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/index"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/quantity"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/roles"
+	"github.com/mrhapile/fluid-ai-diagnoser/pkg/types"
+)
+
+// TieredStoreMisconfiguredRule validates each tiered-store level of a Runtime
+// against its volume type, the worker memory requests and limits, node
+// allocatable memory and worker mount events, citing every inconsistent tier.
+type TieredStoreMisconfiguredRule struct{}
+
+func (r *TieredStoreMisconfiguredRule) ID() string {
+	return "tieredstore-misconfigured"
+}
+
+func (r *TieredStoreMisconfiguredRule) Evaluate(idx *index.Index) []types.Hypothesis {
+	var hypotheses []types.Hypothesis
+
+	for _, name := range idx.RuntimeNames() {
+		runtime := idx.Context().Graph.Runtimes[name]
+		if len(runtime.TieredStore) == 0 {
+			continue
+		}
+		workers := runtimePods(idx, runtime, roles.Worker)
+		memLimit := parseSize(runtime.WorkerLimits["memory"])
+		memRequest := parseSize(runtime.WorkerRequests["memory"])
+		nodeMem := maxAllocatableMemory(idx, workers)
+
+		var evidence []string
+		confidence := types.ConfidenceConditionOnly
+		for i, level := range runtime.TieredStore {
+			tier := fmt.Sprintf("Runtime %s/%s tier %d (%s)", runtime.Namespace, name, i, level.MediumType)
+
+			if strings.TrimSpace(level.Path) == "" {
+				evidence = append(evidence, fmt.Sprintf("%s: no path configured", tier))
+			}
+			quota := parseSize(level.Quota)
+			if quota <= 0 {
+				evidence = append(evidence, fmt.Sprintf("%s: invalid quota %q", tier, level.Quota))
+				continue
+			}
+			if level.MediumType != "MEM" {
+				// An emptyDir lives on the kubelet's root disk, not on the SSD or HDD
+				switch level.VolumeType {
+				case "", "hostPath":
+				case "emptyDir":
+					evidence = append(evidence, fmt.Sprintf("%s: backed by an emptyDir on the node's root disk instead of a hostPath on the %s",
+						tier, level.MediumType))
+				default:
+					evidence = append(evidence, fmt.Sprintf("%s: unknown volumeType %q", tier, level.VolumeType))
+				}
+				continue
+			}
+			// A MEM tier is a ramdisk charged to the worker container
+			if memLimit > 0 && quota >= memLimit {
+				evidence = append(evidence, fmt.Sprintf("%s: quota %s is not below the worker memory limit %s",
+					tier, quantity.FormatBytes(quota), quantity.FormatBytes(memLimit)))
+			} else if memRequest > 0 && quota >= memRequest {
+				evidence = append(evidence, fmt.Sprintf("%s: quota %s is not below the worker memory request %s, so the scheduler reserves less than the ramdisk can use",
+					tier, quantity.FormatBytes(quota), quantity.FormatBytes(memRequest)))
+			}
+			if nodeMem > 0 && quota > nodeMem {
+				evidence = append(evidence, fmt.Sprintf("%s: quota %s exceeds the largest node allocatable memory %s",
+					tier, quantity.FormatBytes(quota), quantity.FormatBytes(nodeMem)))
+			}
+		}
+
+		// Correlate with mount failures on tier paths and OOMKilled workers
+		for _, pod := range workers {
+			for _, event := range idx.EventsFor(index.KindPod, pod.Namespace, pod.Name) {
+				if event.Type != "Warning" || event.Reason != "FailedMount" {
+					continue
+				}
+				for i, level := range runtime.TieredStore {
+					if mentionsTierPath(event.Message, level.Path) {
+						evidence = append(evidence, fmt.Sprintf("Runtime %s/%s tier %d (%s): hostPath missing on pod %s: %s",
+							runtime.Namespace, name, i, level.MediumType, pod.Name, event.Message))
+						confidence = types.ConfidenceEventAndStatus
+						break
+					}
+				}
+			}
+		}
+		if len(evidence) == 0 {
+			continue
+		}
+		for _, pod := range workers {
+			if oomKilled(pod) {
+				evidence = append(evidence, fmt.Sprintf("Pod %s/%s: OOMKilled", pod.Namespace, pod.Name))
+				confidence = types.ConfidenceEventAndStatus
+			}
+		}
+
+		hypotheses = append(hypotheses, types.Hypothesis{
+			Confidence: confidence,
+			Severity:   types.SeverityHigh,
+			Component:  "TieredStore",
+			Issue:      "Runtime tiered store is inconsistent with worker resources or nodes, so workers fail",
+			Evidence:   evidence,
+			Suggestion: "Fix each cited tier in spec.tieredstore.levels: keep MEM quotas below the worker memory request, limit and node allocatable memory, back SSD and HDD tiers with a hostPath on the disk, and make sure every hostPath exists on the worker nodes.",
+			Objects:    []types.ObjectReference{datasetRef(runtime.Namespace, name)},
+		})
+	}
+
+	return hypotheses
+}
+
+// maxAllocatableMemory returns the largest allocatable memory of the nodes
+// running pods, or of all nodes if none of the pods is scheduled. It returns
+// -1 if no node reports allocatable memory.
+func maxAllocatableMemory(idx *index.Index, pods []types.PodInfo) int64 {
+	var nodes []string
+	for _, pod := range pods {
+		if pod.NodeName != "" {
+			nodes = append(nodes, pod.NodeName)
+		}
+	}
+	if len(nodes) == 0 {
+		nodes = idx.NodeNames()
+	}
+
+	largest := int64(-1)
+	for _, name := range nodes {
+		node, ok := idx.Context().Graph.Nodes[name]
+		if !ok {
+			continue
+		}
+		largest = max(largest, parseSize(node.Allocatable["memory"]))
+	}
+	return largest
+}
+
+// mentionsTierPath reports whether message names one of the comma-separated
+// directories of a tier path.
+func mentionsTierPath(message, path string) bool {
+	for _, dir := range strings.Split(path, ",") {
+		if dir = strings.TrimSpace(dir); dir != "" && strings.Contains(message, dir) {
+			return true
+		}
+	}
+	return false
+}
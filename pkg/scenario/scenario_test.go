@@ -0,0 +1,365 @@
+package scenario
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuild_Healthy(t *testing.T) {
+	ctx := New("mydata").Nodes(2).Workers(2).Build()
+
+	runtime := ctx.Graph.Runtimes["mydata"]
+	if runtime.Phase != "Ready" || runtime.WorkerReady != 2 || runtime.MasterReady != 1 {
+		t.Errorf("Expected ready runtime, got phase=%s master=%d worker=%d",
+			runtime.Phase, runtime.MasterReady, runtime.WorkerReady)
+	}
+	if runtime.FuseReady != 2 || runtime.FuseUnavailable != 0 {
+		t.Errorf("Expected 2 ready fuse pods, got ready=%d unavailable=%d", runtime.FuseReady, runtime.FuseUnavailable)
+	}
+	if ctx.Graph.Datasets["mydata"].Status != "Bound" {
+		t.Errorf("Expected dataset to be Bound, got %s", ctx.Graph.Datasets["mydata"].Status)
+	}
+	if ctx.Graph.PVCs["mydata"].Status != "Bound" {
+		t.Error("Expected a bound PVC for a bound dataset")
+	}
+	if len(ctx.Events) != 0 {
+		t.Errorf("Expected no warning events, got %d", len(ctx.Events))
+	}
+	if len(ctx.Graph.Pods) != 1+2+2 {
+		t.Errorf("Expected 5 pods, got %d", len(ctx.Graph.Pods))
+	}
+}
+
+func TestBuild_Failures(t *testing.T) {
+	ctx := New("mydata").
+		Runtime("JuiceFS").
+		Nodes(3).
+		Workers(3).
+		OOMKilledWorkers(1).
+		MemoryPendingWorkers(1).
+		TaintedNodes(1).
+		Build()
+
+	runtime := ctx.Graph.Runtimes["mydata"]
+	if runtime.WorkerReady != 1 || runtime.Phase != "PartialReady" {
+		t.Errorf("Expected 1/3 workers ready and PartialReady, got %d and %s", runtime.WorkerReady, runtime.Phase)
+	}
+	if runtime.FuseUnavailable != 1 {
+		t.Errorf("Expected the fuse pod on the tainted node to be unavailable, got %d", runtime.FuseUnavailable)
+	}
+
+	var pending, oom int
+	for _, pod := range ctx.Graph.Pods {
+		if pod.Status == "Pending" {
+			pending++
+			if pod.NodeName != "" {
+				t.Errorf("Pending pod %s must not be assigned a node", pod.Name)
+			}
+		}
+		for _, cs := range pod.ContainerStatuses {
+			if cs.LastTerminationReason == "OOMKilled" {
+				oom++
+			}
+		}
+		if pod.NodeName == "node-0" {
+			t.Errorf("Pod %s scheduled onto tainted node", pod.Name)
+		}
+		if len(pod.OwnerReferences) == 0 || pod.Labels["release"] != "mydata" {
+			t.Errorf("Pod %s is missing owner references or labels", pod.Name)
+		}
+	}
+	if pending != 2 || oom != 1 {
+		t.Errorf("Expected 2 pending pods and 1 OOMKilled pod, got %d and %d", pending, oom)
+	}
+
+	var sawTaint, sawMemory, sawBackOff bool
+	for _, event := range ctx.Events {
+		sawTaint = sawTaint || strings.Contains(event.Message, "untolerated taint")
+		sawMemory = sawMemory || strings.Contains(event.Message, "Insufficient memory")
+		sawBackOff = sawBackOff || event.Reason == "BackOff"
+	}
+	if !sawTaint || !sawMemory || !sawBackOff {
+		t.Errorf("Missing events: taint=%v memory=%v backoff=%v", sawTaint, sawMemory, sawBackOff)
+	}
+}
+
+func TestBuild_WithoutRuntime(t *testing.T) {
+	ctx := New("mydata").WithoutRuntime().Build()
+
+	if len(ctx.Graph.Runtimes) != 0 || len(ctx.Graph.Pods) != 0 {
+		t.Error("Expected no runtime and no pods")
+	}
+	if ctx.Graph.Datasets["mydata"].Status != "NotBound" {
+		t.Error("Expected dataset to be NotBound without a runtime")
+	}
+}
+
+func TestBuild_ControlPlane(t *testing.T) {
+	ctx := New("mydata").Nodes(3).ControlPlane().Build()
+
+	if len(ctx.Graph.ControlPlane) != 3+3 {
+		t.Errorf("Expected 3 controller/webhook pods and 3 CSI pods, got %d", len(ctx.Graph.ControlPlane))
+	}
+	for name, pod := range ctx.Graph.ControlPlane {
+		if pod.Namespace != "fluid-system" || pod.Status != "Running" {
+			t.Errorf("Expected %s to be running in fluid-system, got %s/%s", name, pod.Namespace, pod.Status)
+		}
+		if _, ok := ctx.Graph.Pods[name]; ok {
+			t.Errorf("Control-plane pod %s should not be in Graph.Pods", name)
+		}
+	}
+	if New("mydata").Build().Graph.ControlPlane != nil {
+		t.Error("Expected no control plane unless requested")
+	}
+}
+
+func TestBuild_ControlPlaneImages(t *testing.T) {
+	for _, pod := range New("mydata").ControlPlane().Build().Graph.ControlPlane {
+		if image := pod.ContainerStatuses[0].Image; image != "" {
+			t.Errorf("Expected no image without a Fluid version, got %q on %s", image, pod.Name)
+		}
+	}
+
+	ctx := New("mydata").ControlPlane().FluidVersion("v1.0.3").CSIPluginVersion("v0.9.3").Build()
+	if ctx.Metadata.CollectorVersion != "v1.0.3" {
+		t.Errorf("Expected the collector to track the Fluid version, got %q", ctx.Metadata.CollectorVersion)
+	}
+	for _, pod := range ctx.Graph.ControlPlane {
+		want := ":v1.0.3-"
+		if strings.HasPrefix(pod.Name, "csi-nodeplugin-fluid-") {
+			want = ":v0.9.3-"
+		}
+		if image := pod.ContainerStatuses[0].Image; !strings.Contains(image, want) {
+			t.Errorf("Expected %s to run an image tagged %s..., got %q", pod.Name, want, image)
+		}
+	}
+}
+
+func TestBuild_CrashLoopingController(t *testing.T) {
+	ctx := New("mydata").Workers(2).CrashLoopingController().Build()
+
+	crashing := 0
+	for _, pod := range ctx.Graph.ControlPlane {
+		for _, cs := range pod.ContainerStatuses {
+			if cs.Reason == "CrashLoopBackOff" && !cs.Ready {
+				crashing++
+			}
+		}
+	}
+	if crashing != 1 {
+		t.Errorf("Expected 1 crash-looping controller, got %d", crashing)
+	}
+
+	runtime := ctx.Graph.Runtimes["mydata"]
+	if runtime.Phase != "NotReady" || runtime.WorkerReady != 0 {
+		t.Errorf("Expected frozen runtime status, got phase=%s worker=%d", runtime.Phase, runtime.WorkerReady)
+	}
+	if ctx.Graph.Datasets["mydata"].Status != "NotBound" {
+		t.Error("Expected frozen dataset status")
+	}
+	if _, ok := ctx.Graph.PVCs["mydata"]; ok {
+		t.Error("Expected no PVC while the controller is down")
+	}
+}
+
+func TestBuild_DataLoad(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		b     *Builder
+		phase string
+		pods  int
+	}{
+		{"complete", New("mydata").DataLoad(), "Complete", 1},
+		{"oom-killed", New("mydata").OOMKilledDataLoad(), "Failed", 1},
+		{"restarted", New("mydata").RestartedDataLoad(), "Executing", 1},
+		{"unbound-dataset", New("mydata").WithoutRuntime().OOMKilledDataLoad(), "Pending", 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := tc.b.Build()
+			op, ok := ctx.Graph.DataOperations["mydata-warmup"]
+			if !ok {
+				t.Fatal("Expected a DataLoad called mydata-warmup")
+			}
+			if op.Phase != tc.phase {
+				t.Errorf("Expected phase %s, got %s", tc.phase, op.Phase)
+			}
+			pods := 0
+			for _, pod := range ctx.Graph.Pods {
+				for _, owner := range pod.OwnerReferences {
+					if owner.Kind == "Job" && owner.Name == op.Job {
+						pods++
+					}
+				}
+			}
+			if pods != tc.pods {
+				t.Errorf("Expected %d job pods, got %d", tc.pods, pods)
+			}
+		})
+	}
+}
+
+func TestBuild_Cache(t *testing.T) {
+	dataset := New("mydata").Cache("4Gi", "10Gi").CachedPercent(25).CacheHitRatio(80).Build().Graph.Datasets["mydata"]
+	if dataset.CacheCapacity != "4.00GiB" || dataset.UfsTotal != "10.00GiB" || dataset.Cached != "2.50GiB" {
+		t.Errorf("Unexpected cache sizes %+v", dataset)
+	}
+	if dataset.CachedPercentage != "25.0%" || dataset.CacheHitRatio != "80.0%" {
+		t.Errorf("Unexpected cache percentages %+v", dataset)
+	}
+
+	// The cache never holds more than its capacity
+	if got := New("mydata").Cache("4Gi", "10Gi").DataLoad().Build().Graph.Datasets["mydata"].Cached; got != "4.00GiB" {
+		t.Errorf("Expected a completed DataLoad to fill the cache, got %s", got)
+	}
+	if got := New("mydata").WithoutRuntime().Cache("4Gi", "10Gi").Build().Graph.Datasets["mydata"]; got.CacheCapacity != "" {
+		t.Errorf("Expected no cache statistics on an unbound dataset, got %+v", got)
+	}
+}
+
+func TestBuild_TieredStore(t *testing.T) {
+	runtime := New("mydata").Build().Graph.Runtimes["mydata"]
+	if len(runtime.TieredStore) != 1 || runtime.TieredStore[0].MediumType != "MEM" || runtime.WorkerLimits["memory"] != "4Gi" {
+		t.Errorf("Expected a default MEM tier and worker memory limit, got %+v", runtime)
+	}
+
+	ctx := New("mydata").Workers(3).MemoryPendingWorkers(1).WorkerMemoryLimit("2Gi").Build()
+	if got := ctx.Graph.Runtimes["mydata"].WorkerReady; got != 0 {
+		t.Errorf("Expected no ready workers with a MEM tier at the memory limit, got %d", got)
+	}
+	oom := 0
+	for _, pod := range ctx.Graph.Pods {
+		for _, cs := range pod.ContainerStatuses {
+			if cs.LastTerminationReason == "OOMKilled" {
+				oom++
+			}
+		}
+	}
+	if oom != 2 {
+		t.Errorf("Expected 2 OOMKilled workers, got %d", oom)
+	}
+}
+
+func TestBuild_EvictedWorkers(t *testing.T) {
+	ctx := New("mydata").Nodes(2).Workers(2).EvictedWorkers(1).Build()
+	pod := ctx.Graph.Pods["mydata-worker-0"]
+	if pod.Status != "Failed" || pod.Reason != "Evicted" {
+		t.Fatalf("Expected worker-0 to be evicted, got %+v", pod)
+	}
+	if got := ctx.Graph.Runtimes["mydata"].WorkerReady; got != 1 {
+		t.Errorf("Expected 1 ready worker, got %d", got)
+	}
+	for name, node := range ctx.Graph.Nodes {
+		for _, cond := range node.Conditions {
+			if cond.Type == "DiskPressure" && (cond.Status == "True") != (name == pod.NodeName) {
+				t.Errorf("Expected DiskPressure only on %s, got %s=%s on %s", pod.NodeName, cond.Type, cond.Status, name)
+			}
+		}
+	}
+}
+
+func TestBuild_WorkerNodeSelector(t *testing.T) {
+	ctx := New("mydata").Nodes(2).WorkerNodeSelector(map[string]string{"kubernetes.io/hostname": "node-1"}).Workers(2).Build()
+	for _, name := range []string{"mydata-worker-0", "mydata-worker-1"} {
+		if got := ctx.Graph.Pods[name].NodeName; got != "node-1" {
+			t.Errorf("Expected %s on node-1, got %q", name, got)
+		}
+	}
+
+	ctx = New("mydata").WorkerNodeSelector(map[string]string{"disktype": "ssd"}).Build()
+	want := "0/1 nodes are available: 1 node(s) didn't match Pod's node affinity/selector."
+	if pod := ctx.Graph.Pods["mydata-worker-0"]; pod.Status != "Pending" || pod.Conditions[0].Message != want {
+		t.Errorf("Expected the worker Pending with %q, got %+v", want, pod)
+	}
+	if got := ctx.Graph.Runtimes["mydata"].WorkerNodeSelector["disktype"]; got != "ssd" {
+		t.Errorf("Expected the selector on the Runtime, got %q", got)
+	}
+}
+
+func TestBuild_Storage(t *testing.T) {
+	ctx := New("mydata").Build()
+	pvc := ctx.Graph.PVCs["mydata"]
+	pv, ok := ctx.Graph.PVs[pvc.VolumeName]
+	if !ok || pv.Status != "Bound" || pv.ClaimRef == nil || pv.ClaimRef.Name != "mydata" {
+		t.Fatalf("Expected a PV bound to the Fluid PVC, got %+v", pv)
+	}
+	if pv.StorageClassName != pvc.StorageClassName || pv.Labels["fluid.io/s-default-mydata"] != "true" {
+		t.Errorf("Expected the PV to match the PVC's class and selector, got %+v", pv)
+	}
+
+	ctx = New("mydata").MissingPV().Build()
+	if len(ctx.Graph.PVs) != 0 || ctx.Graph.PVCs["mydata"].Status != "Pending" {
+		t.Errorf("Expected a pending PVC and no PV, got %+v", ctx.Graph.PVCs["mydata"])
+	}
+}
+
+func TestBuild_AppPods(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		b      *Builder
+		status string
+	}{
+		{"bound", New("mydata").Nodes(2).AppPods(3), "Running"},
+		{"unbound", New("mydata").WithoutRuntime().AppPods(3), "Pending"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			apps := 0
+			for _, pod := range tc.b.Build().Graph.Pods {
+				if len(pod.PVCs) == 0 {
+					continue
+				}
+				apps++
+				if pod.Status != tc.status || pod.PVCs[0] != "mydata" {
+					t.Errorf("Expected %s to be %s and mount mydata, got %s %v", pod.Name, tc.status, pod.Status, pod.PVCs)
+				}
+			}
+			if apps != 3 {
+				t.Errorf("Expected 3 app pods, got %d", apps)
+			}
+		})
+	}
+}
+
+func TestBuild_FuseSidecar(t *testing.T) {
+	for _, pod := range New("mydata").AppPods(2).FuseSidecar().Build().Graph.Pods {
+		if pod.Labels["serverless.fluid.io/inject"] != "true" {
+			continue
+		}
+		if len(pod.ContainerStatuses) != 2 || pod.ContainerStatuses[1].Name != "fluid-fuse-0" || !pod.ContainerStatuses[1].Ready {
+			t.Errorf("Expected a ready fluid-fuse-0 sidecar in %s, got %+v", pod.Name, pod.ContainerStatuses)
+		}
+	}
+
+	for _, pod := range New("mydata").AppPods(1).FuseSidecarNotInjected().Build().Graph.Pods {
+		if pod.Labels["serverless.fluid.io/inject"] == "true" && len(pod.ContainerStatuses) != 1 {
+			t.Errorf("Expected only the app container in %s, got %+v", pod.Name, pod.ContainerStatuses)
+		}
+	}
+}
+
+func TestBuild_Deterministic(t *testing.T) {
+	b := New("mydata").Nodes(4).Workers(5).TaintedNodes(2).OOMKilledWorkers(1)
+	if !reflect.DeepEqual(b.Build(), b.Build()) {
+		t.Error("Expected repeated builds to be identical")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := New("sales").Namespace("team-a").Workers(2).AppPods(1).Build()
+	b := New("ads").Namespace("team-b").Workers(1).MissingPV().Build()
+	ctx := Merge(a, b)
+
+	if len(ctx.Graph.Datasets) != 2 || len(ctx.Graph.Pods) != len(a.Graph.Pods)+len(b.Graph.Pods) {
+		t.Errorf("Expected both tenants' Datasets and pods, got %d Datasets and %d pods",
+			len(ctx.Graph.Datasets), len(ctx.Graph.Pods))
+	}
+	if len(ctx.Events) != len(a.Events)+len(b.Events) {
+		t.Errorf("Expected %d events, got %d", len(a.Events)+len(b.Events), len(ctx.Events))
+	}
+	if ctx.Summary.Namespace != "team-a" {
+		t.Errorf("Expected the first context's summary, got %+v", ctx.Summary)
+	}
+	if len(a.Graph.Datasets) != 1 {
+		t.Error("Expected Merge to leave its inputs untouched")
+	}
+}
@@ -0,0 +1,188 @@
+// Package scheduling parses the messages the Kubernetes scheduler attaches
+// to FailedScheduling events and PodScheduled=False conditions, such as
+//
+//	0/5 nodes are available: 1 Insufficient memory, 2 node(s) had untolerated
+//	taint {dedicated: gpu}, 2 node(s) didn't match Pod's node affinity/selector.
+//
+// into per-reason node counts, so rules can match on the reason instead of
+// searching the raw text.
+package scheduling
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Category classifies why the scheduler rejected a set of nodes.
+type Category string
+
+const (
+	Insufficient  Category = "Insufficient"  // Insufficient cpu, memory, ...
+	Taint         Category = "Taint"         // untolerated taint
+	NodeAffinity  Category = "NodeAffinity"  // node affinity or nodeSelector mismatch
+	PodAffinity   Category = "PodAffinity"   // pod affinity or anti-affinity rules
+	Unschedulable Category = "Unschedulable" // cordoned nodes
+	Volume        Category = "Volume"        // volume binding, zone or attach limits
+	Ports         Category = "Ports"         // host ports in use
+	Other         Category = "Other"
+)
+
+// Reason is one entry of a scheduler message.
+type Reason struct {
+	// Nodes is the number of nodes rejected for this reason, or 0 for
+	// reasons about the pod itself, such as unbound PVCs.
+	Nodes    int
+	Category Category
+	// Detail is the resource for Insufficient ("memory") and the taint for
+	// Taint ("dedicated: gpu").
+	Detail string
+	// Text is the reason as the scheduler wrote it, without the count.
+	Text string
+}
+
+// Message is a parsed scheduler message.
+type Message struct {
+	// Available and Total are the "0/5" of "0/5 nodes are available". Total
+	// is 0 if the message has no such header.
+	Available int
+	Total     int
+	Reasons   []Reason
+}
+
+var (
+	header      = regexp.MustCompile(`^(\d+)/(\d+) nodes are available:?\s*`)
+	reasonStart = regexp.MustCompile(`(?:^|, )(\d+) `)
+	braces      = regexp.MustCompile(`\{([^}]*)\}`)
+)
+
+// Parse parses a scheduler message. ok is false when the message is neither
+// in the "N/M nodes are available" format nor names a known reason, in
+// which case it should be cited verbatim.
+func Parse(message string) (m Message, ok bool) {
+	rest := strings.TrimSpace(message)
+	if match := header.FindStringSubmatch(rest); match != nil {
+		m.Available, _ = strconv.Atoi(match[1])
+		m.Total, _ = strconv.Atoi(match[2])
+		rest = rest[len(match[0]):]
+		ok = true
+	}
+	// Since 1.24 the scheduler appends its preemption verdict
+	if i := strings.Index(rest, " preemption:"); i >= 0 {
+		rest = rest[:i]
+	}
+	rest = strings.TrimSuffix(strings.TrimSpace(rest), ".")
+
+	starts := reasonStart.FindAllStringSubmatchIndex(rest, -1)
+	if len(starts) == 0 || starts[0][0] > 0 {
+		end := len(rest)
+		if len(starts) > 0 {
+			end = starts[0][0]
+		}
+		if text := strings.TrimSpace(rest[:end]); text != "" {
+			m.Reasons = append(m.Reasons, newReason(0, text))
+		}
+	}
+	for i, s := range starts {
+		end := len(rest)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		nodes, _ := strconv.Atoi(rest[s[2]:s[3]])
+		m.Reasons = append(m.Reasons, newReason(nodes, rest[s[1]:end]))
+	}
+
+	for _, r := range m.Reasons {
+		if r.Category != Other {
+			ok = true
+		}
+	}
+	return m, ok
+}
+
+func newReason(nodes int, text string) Reason {
+	r := Reason{Nodes: nodes, Category: Other, Text: text}
+	lower := strings.ToLower(text)
+	switch {
+	case strings.HasPrefix(lower, "insufficient "):
+		r.Category = Insufficient
+		r.Detail = strings.TrimSpace(text[len("insufficient "):])
+	case strings.Contains(lower, "taint"):
+		r.Category = Taint
+		if match := braces.FindStringSubmatch(text); match != nil {
+			r.Detail = match[1]
+		}
+	// Before node affinity: "volume node affinity conflict" is about volumes
+	case strings.Contains(lower, "volume"):
+		r.Category = Volume
+	case strings.Contains(lower, "node affinity"), strings.Contains(lower, "node selector"):
+		r.Category = NodeAffinity
+	case strings.Contains(lower, "affinity"):
+		r.Category = PodAffinity
+	case strings.Contains(lower, "unschedulable"):
+		r.Category = Unschedulable
+	case strings.Contains(lower, "free ports"):
+		r.Category = Ports
+	}
+	return r
+}
+
+// Count returns the number of nodes rejected for reasons in category c.
+// Reasons about the pod itself count as one.
+func (m Message) Count(c Category) int {
+	n := 0
+	for _, r := range m.Reasons {
+		if r.Category == c {
+			n += max(r.Nodes, 1)
+		}
+	}
+	return n
+}
+
+// Insufficient returns the number of nodes lacking resource, e.g. "memory".
+func (m Message) Insufficient(resource string) int {
+	n := 0
+	for _, r := range m.Reasons {
+		if r.Category == Insufficient && strings.EqualFold(r.Detail, resource) {
+			n += max(r.Nodes, 1)
+		}
+	}
+	return n
+}
+
+// String renders the breakdown compactly, e.g.
+// "0/5 nodes available [insufficient memory: 1, taint {dedicated: gpu}: 2,
+// node affinity/selector mismatch: 2]".
+func (m Message) String() string {
+	var parts []string
+	for _, r := range m.Reasons {
+		label := r.label()
+		if r.Nodes > 0 {
+			label = fmt.Sprintf("%s: %d", label, r.Nodes)
+		}
+		parts = append(parts, label)
+	}
+	breakdown := strings.Join(parts, ", ")
+	if m.Total == 0 {
+		return breakdown
+	}
+	return fmt.Sprintf("%d/%d nodes available [%s]", m.Available, m.Total, breakdown)
+}
+
+func (r Reason) label() string {
+	switch r.Category {
+	case Insufficient:
+		return "insufficient " + r.Detail
+	case Taint:
+		if r.Detail == "" {
+			return "taint"
+		}
+		return "taint {" + r.Detail + "}"
+	case NodeAffinity:
+		return "node affinity/selector mismatch"
+	case Unschedulable:
+		return "node unschedulable"
+	}
+	return r.Text
+}
@@ -0,0 +1,90 @@
+package scheduling
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    Message
+	}{
+		{
+			name:    "mixed reasons",
+			message: "0/5 nodes are available: 1 Insufficient memory, 2 node(s) had untolerated taint {dedicated: gpu}, 2 node(s) didn't match Pod's node affinity/selector. preemption: 0/5 nodes are available: 5 Preemption is not helpful for scheduling.",
+			want: Message{Total: 5, Reasons: []Reason{
+				{Nodes: 1, Category: Insufficient, Detail: "memory", Text: "Insufficient memory"},
+				{Nodes: 2, Category: Taint, Detail: "dedicated: gpu", Text: "node(s) had untolerated taint {dedicated: gpu}"},
+				{Nodes: 2, Category: NodeAffinity, Text: "node(s) didn't match Pod's node affinity/selector"},
+			}},
+		},
+		{
+			name:    "legacy taint wording with a comma",
+			message: "0/3 nodes are available: 3 node(s) had taint {node-role.kubernetes.io/master: }, that the pod didn't tolerate.",
+			want: Message{Total: 3, Reasons: []Reason{
+				{Nodes: 3, Category: Taint, Detail: "node-role.kubernetes.io/master: ", Text: "node(s) had taint {node-role.kubernetes.io/master: }, that the pod didn't tolerate"},
+			}},
+		},
+		{
+			name:    "pod-level reason",
+			message: "0/2 nodes are available: pod has unbound immediate PersistentVolumeClaims.",
+			want: Message{Total: 2, Reasons: []Reason{
+				{Category: Volume, Text: "pod has unbound immediate PersistentVolumeClaims"},
+			}},
+		},
+		{
+			name:    "volume node affinity is a volume reason",
+			message: "0/4 nodes are available: 1 node(s) were unschedulable, 3 node(s) had volume node affinity conflict.",
+			want: Message{Total: 4, Reasons: []Reason{
+				{Nodes: 1, Category: Unschedulable, Text: "node(s) were unschedulable"},
+				{Nodes: 3, Category: Volume, Text: "node(s) had volume node affinity conflict"},
+			}},
+		},
+		{
+			name:    "bare reason",
+			message: "Insufficient memory",
+			want: Message{Reasons: []Reason{
+				{Category: Insufficient, Detail: "memory", Text: "Insufficient memory"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.message)
+			if !ok {
+				t.Fatalf("Parse(%q) reported not ok", tt.message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Unrecognised(t *testing.T) {
+	for _, message := range []string{"", "running Reserve plugin \"Coscheduling\": rejected"} {
+		if m, ok := Parse(message); ok {
+			t.Errorf("Expected Parse(%q) to fail, got %+v", message, m)
+		}
+	}
+}
+
+func TestMessage_Counts(t *testing.T) {
+	m, _ := Parse("0/6 nodes are available: 2 Insufficient memory, 1 Insufficient cpu, 3 node(s) had untolerated taint {a: b}.")
+	if got := m.Insufficient("memory"); got != 2 {
+		t.Errorf("Insufficient(memory) = %d, want 2", got)
+	}
+	if got := m.Count(Insufficient); got != 3 {
+		t.Errorf("Count(Insufficient) = %d, want 3", got)
+	}
+	if got := m.Count(NodeAffinity); got != 0 {
+		t.Errorf("Count(NodeAffinity) = %d, want 0", got)
+	}
+	want := "0/6 nodes available [insufficient memory: 2, insufficient cpu: 1, taint {a: b}: 3]"
+	if got := m.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
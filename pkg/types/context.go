@@ -12,6 +12,7 @@ type DiagnosticContext struct {
 type Summary struct {
 	ClusterVersion string `json:"clusterVersion"`
 	Namespace      string `json:"namespace"`
+	FluidVersion   string `json:"fluidVersion,omitempty"` // installed Fluid release, e.g. "v1.0.3"
 }
 
 type ResourceGraph struct {
@@ -20,14 +21,29 @@ type ResourceGraph struct {
 	PVCs     map[string]PVCInfo     `json:"pvcs,omitempty"`
 	Datasets map[string]DatasetInfo `json:"datasets,omitempty"`
 	Runtimes map[string]RuntimeInfo `json:"runtimes,omitempty"`
+
+	// PVs and StorageClasses are cluster-scoped and keyed by name. A nil map
+	// means they were not collected.
+	PVs            map[string]PVInfo           `json:"pvs,omitempty"`
+	StorageClasses map[string]StorageClassInfo `json:"storageClasses,omitempty"`
+
+	// ControlPlane holds the Fluid control-plane pods (dataset and runtime
+	// controllers, webhook, CSI plugin), usually from the fluid-system namespace.
+	ControlPlane map[string]PodInfo `json:"controlPlane,omitempty"`
+
+	// DataOperations holds Fluid data operations (DataLoad, DataMigrate,
+	// DataBackup, DataProcess). Their job pods are in Pods.
+	DataOperations map[string]DataOperationInfo `json:"dataOperations,omitempty"`
 }
 
 type NodeInfo struct {
 	Name          string            `json:"name"`
+	Labels        map[string]string `json:"labels,omitempty"`
 	Taints        []Taint           `json:"taints,omitempty"`
 	Allocatable   map[string]string `json:"allocatable,omitempty"`
 	Capacity      map[string]string `json:"capacity,omitempty"`
 	Unschedulable bool              `json:"unschedulable,omitempty"`
+	Conditions    []Condition       `json:"conditions,omitempty"` // Ready, MemoryPressure, DiskPressure, PIDPressure
 }
 
 type Taint struct {
@@ -37,13 +53,30 @@ type Taint struct {
 }
 
 type PodInfo struct {
-	Name            string            `json:"name"`
-	Namespace       string            `json:"namespace"`
-	Status          string            `json:"status"` // Pending, Running, Failed, etc.
-	Conditions      []Condition       `json:"conditions,omitempty"`
-	Events          []Event           `json:"events,omitempty"`
-	OwnerReferences []OwnerReference  `json:"ownerReferences,omitempty"`
-	Labels          map[string]string `json:"labels,omitempty"`
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	Status            string            `json:"status"`            // Pending, Running, Failed, etc.
+	Reason            string            `json:"reason,omitempty"`  // e.g. Evicted
+	Message           string            `json:"message,omitempty"` // e.g. "The node was low on resource: ephemeral-storage."
+	NodeName          string            `json:"nodeName,omitempty"`
+	Conditions        []Condition       `json:"conditions,omitempty"`
+	ContainerStatuses []ContainerStatus `json:"containerStatuses,omitempty"`
+	Events            []Event           `json:"events,omitempty"`
+	OwnerReferences   []OwnerReference  `json:"ownerReferences,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	PVCs              []string          `json:"pvcs,omitempty"` // claims mounted, in the pod's namespace
+}
+
+type ContainerStatus struct {
+	Name                  string `json:"name"`
+	Image                 string `json:"image,omitempty"` // e.g. "fluidcloudnative/dataset-controller:v1.0.3-a1b2c3d"
+	Ready                 bool   `json:"ready"`
+	RestartCount          int32  `json:"restartCount,omitempty"`
+	State                 string `json:"state,omitempty"`  // Waiting, Running, Terminated
+	Reason                string `json:"reason,omitempty"` // CrashLoopBackOff, OOMKilled, etc.
+	ExitCode              int32  `json:"exitCode,omitempty"`
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
 }
 
 type Condition struct {
@@ -64,6 +97,30 @@ type PVCInfo struct {
 	Status     string      `json:"status"` // Bound, Pending, Lost
 	VolumeName string      `json:"volumeName,omitempty"`
 	Conditions []Condition `json:"conditions,omitempty"`
+
+	StorageClassName string            `json:"storageClassName,omitempty"`
+	AccessModes      []string          `json:"accessModes,omitempty"` // ReadWriteOnce, ReadOnlyMany, ReadWriteMany, ReadWriteOncePod
+	RequestedStorage string            `json:"requestedStorage,omitempty"`
+	Selector         map[string]string `json:"selector,omitempty"` // spec.selector.matchLabels
+}
+
+type PVInfo struct {
+	Name             string            `json:"name"`
+	Status           string            `json:"status"` // Available, Bound, Released, Failed, Pending
+	StorageClassName string            `json:"storageClassName,omitempty"`
+	AccessModes      []string          `json:"accessModes,omitempty"`
+	Capacity         string            `json:"capacity,omitempty"`
+	ClaimRef         *ObjectReference  `json:"claimRef,omitempty"`
+	CSIDriver        string            `json:"csiDriver,omitempty"` // e.g. fuse.csi.fluid.io
+	Labels           map[string]string `json:"labels,omitempty"`
+	Message          string            `json:"message,omitempty"`
+}
+
+type StorageClassInfo struct {
+	Name              string `json:"name"`
+	Provisioner       string `json:"provisioner"`
+	VolumeBindingMode string `json:"volumeBindingMode,omitempty"` // Immediate, WaitForFirstConsumer
+	Default           bool   `json:"default,omitempty"`
 }
 
 type DatasetInfo struct {
@@ -71,6 +128,18 @@ type DatasetInfo struct {
 	Namespace  string      `json:"namespace"`
 	Status     string      `json:"status"` // Bound, NotBound
 	Conditions []Condition `json:"conditions,omitempty"`
+
+	// NodeAffinity holds spec.nodeAffinity.required.nodeSelectorTerms,
+	// which Fluid applies to the Runtime's workers. Terms are ORed.
+	NodeAffinity []NodeSelectorTerm `json:"nodeAffinity,omitempty"`
+
+	// Cache statistics as reported in the Dataset status, e.g. "2.00GiB"
+	// and "25.0%". Empty when the runtime has not reported them.
+	CacheCapacity    string `json:"cacheCapacity,omitempty"`
+	Cached           string `json:"cached,omitempty"`
+	CachedPercentage string `json:"cachedPercentage,omitempty"`
+	CacheHitRatio    string `json:"cacheHitRatio,omitempty"`
+	UfsTotal         string `json:"ufsTotal,omitempty"`
 }
 
 type RuntimeInfo struct {
@@ -86,6 +155,43 @@ type RuntimeInfo struct {
 	FusePhase       string      `json:"fusePhase,omitempty"`
 	FuseReady       int32       `json:"fuseReady,omitempty"`
 	FuseUnavailable int32       `json:"fuseUnavailable,omitempty"`
+
+	TieredStore    []TieredStoreLevel `json:"tieredStore,omitempty"`
+	WorkerRequests map[string]string  `json:"workerRequests,omitempty"` // e.g. {"memory": "4Gi"}
+	WorkerLimits   map[string]string  `json:"workerLimits,omitempty"`
+
+	WorkerNodeSelector map[string]string `json:"workerNodeSelector,omitempty"` // spec.worker.nodeSelector
+}
+
+// NodeSelectorTerm is one term of a required node affinity. Its
+// requirements are ANDed.
+type NodeSelectorTerm struct {
+	MatchExpressions []NodeSelectorRequirement `json:"matchExpressions"`
+}
+
+type NodeSelectorRequirement struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"` // In, NotIn, Exists, DoesNotExist, Gt, Lt
+	Values   []string `json:"values,omitempty"`
+}
+
+// TieredStoreLevel is one level of a Runtime's tiered store, as in
+// spec.tieredstore.levels.
+type TieredStoreLevel struct {
+	MediumType string `json:"mediumType"`           // MEM, SSD, HDD
+	VolumeType string `json:"volumeType,omitempty"` // hostPath (default), emptyDir
+	Path       string `json:"path"`                 // comma-separated for multiple directories
+	Quota      string `json:"quota"`                // e.g. "2Gi"
+}
+
+type DataOperationInfo struct {
+	Name       string      `json:"name"`
+	Namespace  string      `json:"namespace"`
+	Kind       string      `json:"kind"`    // DataLoad, DataMigrate, DataBackup, DataProcess
+	Dataset    string      `json:"dataset"` // target Dataset, in the same namespace
+	Phase      string      `json:"phase"`   // Pending, Executing, Complete, Failed
+	Job        string      `json:"job,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 type FailureHint struct {
@@ -95,11 +201,11 @@ type FailureHint struct {
 }
 
 type Event struct {
-	Reason        string `json:"reason"`
-	Message       string `json:"message"`
-	Type          string `json:"type"` // Normal, Warning
-	Count         int32  `json:"count"`
-	LastTimestamp string `json:"lastTimestamp"`
+	Reason         string          `json:"reason"`
+	Message        string          `json:"message"`
+	Type           string          `json:"type"` // Normal, Warning
+	Count          int32           `json:"count"`
+	LastTimestamp  string          `json:"lastTimestamp"`
 	InvolvedObject ObjectReference `json:"involvedObject"`
 }
 
@@ -0,0 +1,40 @@
+package types
+
+// Verdict is the overall state of the analysed context.
+type Verdict string
+
+const (
+	// VerdictHealthy means the collected objects were inspected and no rule
+	// found a problem.
+	VerdictHealthy Verdict = "Healthy"
+
+	// VerdictDegraded means problems were found, none of them critical.
+	VerdictDegraded Verdict = "Degraded"
+
+	// VerdictUnavailable means at least one critical problem was found, such
+	// as a Dataset whose master is down.
+	VerdictUnavailable Verdict = "Unavailable"
+
+	// VerdictInsufficientData means no problem was found but the context was
+	// too thin to call it healthy: it lacked Datasets, Runtimes, pods or
+	// nodes, or rules failed.
+	VerdictInsufficientData Verdict = "InsufficientData"
+)
+
+// Health summarises a diagnosis so that "nothing found" can be told apart
+// from "nothing looked at".
+type Health struct {
+	Verdict  Verdict  `json:"verdict"`
+	Coverage Coverage `json:"coverage"`
+}
+
+// Coverage records what the analysis had to work with.
+type Coverage struct {
+	// Inspected counts the objects of each kind, e.g. "Dataset": 2. Kinds
+	// with no objects are omitted.
+	Inspected map[string]int `json:"inspected"`
+
+	// Missing names the sections of the context that were absent or empty,
+	// by their JSON field, e.g. "events" or "graph.controlPlane".
+	Missing []string `json:"missing,omitempty"`
+}
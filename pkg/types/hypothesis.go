@@ -3,8 +3,24 @@ package types
 type Hypothesis struct {
 	Rank       int      `json:"rank"`
 	Confidence float64  `json:"confidence"`
+	Severity   int      `json:"severity,omitempty"` // SeverityCritical..SeverityLow, 0 if unset
 	Component  string   `json:"component"`
 	Issue      string   `json:"issue"`
 	Evidence   []string `json:"evidence"`
 	Suggestion string   `json:"suggestion"`
+
+	// Objects are the Datasets, PVCs and Nodes the hypothesis is about.
+	// Rules leave it empty for cluster-wide issues such as the controller.
+	Objects []ObjectReference `json:"objects,omitempty"`
+
+	// Impact is the blast radius of Objects, filled in by the engine. It is
+	// nil when no application pod is affected.
+	Impact *Impact `json:"impact,omitempty"`
+}
+
+// Impact lists the application pods affected by a hypothesis.
+type Impact struct {
+	Pods       []string `json:"pods"` // namespace/name
+	Namespaces []string `json:"namespaces"`
+	Workloads  []string `json:"workloads"` // e.g. "Deployment default/trainer"
 }
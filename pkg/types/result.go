@@ -1,9 +1,42 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type DiagnosisResult struct {
-	Hypotheses  []Hypothesis `json:"hypotheses"`
-	GeneratedAt time.Time    `json:"generatedAt"`
-	Engine      string       `json:"engine"` // "rule-based"
+	Hypotheses      []Hypothesis `json:"hypotheses"`
+	GeneratedAt     time.Time    `json:"generatedAt"`
+	Engine          string       `json:"engine"` // "rule-based"
+	RulePack        string       `json:"rulePack,omitempty"`
+	RulePackVersion string       `json:"rulePackVersion,omitempty"`
+	RuleErrors      []RuleError  `json:"ruleErrors,omitempty"`
+	Scope           string       `json:"scope,omitempty"` // e.g. "dataset team-a/mydata"; empty for the whole context
+	Health          Health       `json:"health"`
+
+	// Datasets and Cluster group the hypotheses when requested with
+	// engine.WithDatasetGrouping. Cluster holds those not tied to a Dataset,
+	// such as node and control-plane problems.
+	Datasets []DatasetResult `json:"datasets,omitempty"`
+	Cluster  []Hypothesis    `json:"cluster,omitempty"`
+}
+
+// DatasetResult holds the hypotheses concerning one Dataset, ranked within
+// the Dataset. A healthy Dataset has none.
+type DatasetResult struct {
+	Namespace  string       `json:"namespace"`
+	Name       string       `json:"name"`
+	Hypotheses []Hypothesis `json:"hypotheses"`
+}
+
+// RuleError records a rule that failed while being evaluated.
+type RuleError struct {
+	RuleID  string `json:"ruleId"`
+	Phase   string `json:"phase"` // evaluate, match, hypothesis
+	Message string `json:"message"`
+}
+
+func (e RuleError) Error() string {
+	return fmt.Sprintf("rule %s failed during %s: %s", e.RuleID, e.Phase, e.Message)
 }
@@ -0,0 +1,158 @@
+// Package version provides minimal semantic version parsing and range
+// matching. It understands the version strings found in a DiagnosticContext,
+// such as Kubernetes server versions ("v1.28.3-eks-4f4795d") and collector
+// versions ("v0.1.0").
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed MAJOR.MINOR.PATCH version. Pre-release and build
+// suffixes are discarded because rule selection only cares about releases.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// Parse parses a version string. A leading "v" is optional, missing minor or
+// patch components default to zero, and anything after the first "-" or "+"
+// is ignored.
+func Parse(s string) (Version, error) {
+	raw := strings.TrimSpace(s)
+	raw = strings.TrimPrefix(raw, "v")
+	if i := strings.IndexAny(raw, "-+"); i >= 0 {
+		raw = raw[:i]
+	}
+	if raw == "" {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	var nums [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		nums[i] = n
+	}
+
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}, nil
+}
+
+// Compare returns -1, 0 or 1 depending on whether v is lower than, equal to
+// or higher than o.
+func (v Version) Compare(o Version) int {
+	switch {
+	case v.Major != o.Major:
+		return cmpInt(v.Major, o.Major)
+	case v.Minor != o.Minor:
+		return cmpInt(v.Minor, o.Minor)
+	default:
+		return cmpInt(v.Patch, o.Patch)
+	}
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Constraint is a conjunction of comparisons, e.g. ">=1.20 <1.30".
+// The zero value matches every version.
+type Constraint struct {
+	raw   string
+	terms []term
+}
+
+type term struct {
+	op      string
+	version Version
+}
+
+// ParseConstraint parses a whitespace- or comma-separated list of
+// comparisons. Supported operators are =, !=, >, >=, < and <=; a bare
+// version means =. An empty string yields a constraint matching everything.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: strings.TrimSpace(s)}
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\t'
+	})
+
+	for _, f := range fields {
+		op := "="
+		for _, candidate := range []string{">=", "<=", "!=", ">", "<", "="} {
+			if strings.HasPrefix(f, candidate) {
+				op = candidate
+				f = strings.TrimPrefix(f, candidate)
+				break
+			}
+		}
+		v, err := Parse(f)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: %w", s, err)
+		}
+		c.terms = append(c.terms, term{op: op, version: v})
+	}
+
+	return c, nil
+}
+
+// MustParseConstraint is like ParseConstraint but panics on a malformed
+// constraint. It is meant for constraints written into the source, such as
+// support tables.
+func MustParseConstraint(s string) Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Check reports whether v satisfies every comparison in the constraint.
+func (c Constraint) Check(v Version) bool {
+	for _, t := range c.terms {
+		cmp := v.Compare(t.version)
+		var ok bool
+		switch t.op {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
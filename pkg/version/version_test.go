@@ -0,0 +1,76 @@
+package version
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	cases := map[string]Version{
+		"v1.28.0":             {1, 28, 0},
+		"1.28":                {1, 28, 0},
+		"v1.28.3-eks-4f4795d": {1, 28, 3},
+		"v1.27.6+k3s1":        {1, 27, 6},
+		"v0.1.0":              {0, 1, 0},
+	}
+	for in, want := range cases {
+		got, err := Parse(in)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("Parse(%q) = %v, want %v", in, got, want)
+		}
+	}
+
+	for _, in := range []string{"", "v", "latest", "1.2.3.4", "1.x"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Expected Parse(%q) to fail", in)
+		}
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	cases := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"", "v1.28.0", true},
+		{">=1.20 <1.30", "v1.28.0", true},
+		{">=1.20 <1.30", "v1.30.0", false},
+		{">=1.20, <1.30", "v1.19.9", false},
+		{"1.28.0", "v1.28.0", true},
+		{"!=1.28.0", "v1.28.0", false},
+		{">0.9", "v1.0.0", true},
+		{"<=0.9", "v0.9.0", true},
+	}
+	for _, tc := range cases {
+		c, err := ParseConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q) returned error: %v", tc.constraint, err)
+		}
+		v, err := Parse(tc.version)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", tc.version, err)
+		}
+		if got := c.Check(v); got != tc.want {
+			t.Errorf("%q.Check(%s) = %v, want %v", tc.constraint, tc.version, got, tc.want)
+		}
+	}
+
+	if _, err := ParseConstraint(">=one"); err == nil {
+		t.Error("Expected ParseConstraint to reject a malformed version")
+	}
+}
+
+func TestMustParseConstraint(t *testing.T) {
+	if c := MustParseConstraint(">=1.18 <1.29"); !c.Check(Version{1, 28, 0}) {
+		t.Errorf("Expected %s to accept v1.28.0", c)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustParseConstraint to panic on a malformed constraint")
+		}
+	}()
+	MustParseConstraint(">=one")
+}